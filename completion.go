@@ -0,0 +1,207 @@
+package zconfig
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	TagComplete = "complete"
+
+	CompleteFile = "file"
+	CompleteDir  = "dir"
+)
+
+// A completionFlag describes a flag in a completion script. Its values are
+// completed from a fixed list (values), as file or directory paths (kind), or
+// not at all.
+type completionFlag struct {
+	name   string
+	desc   string
+	kind   string
+	values []string
+}
+
+// completionFlags returns the flags of the configurable fields, sorted by key,
+// followed by the special flags handled by zconfig. The boolean fields are
+// completed with true and false, and the fields with a `complete` tag set to
+// file or dir with the corresponding paths.
+func completionFlags(fields []*Field) (flags []completionFlag) {
+	for _, option := range usageOptions(fields) {
+		flag := completionFlag{
+			name: option.Flag,
+			desc: option.Description,
+			kind: option.field.Tags.Get(TagComplete),
+		}
+
+		typ := option.field.Value.Type()
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if flag.kind == "" && typ.Kind() == reflect.Bool {
+			flag.values = []string{"true", "false"}
+		}
+
+		flags = append(flags, flag)
+	}
+
+	return append(flags,
+		completionFlag{name: "help", desc: "display the help message", values: []string{"cli", "env", "markdown", "json", "man", "values"}},
+		completionFlag{name: "completion", desc: "generate a shell completion script", values: []string{"bash", "zsh", "fish"}},
+		completionFlag{name: "config", desc: "configuration files to load", kind: CompleteFile},
+		completionFlag{name: "dotenv", desc: "dotenv file to load", kind: CompleteFile},
+	)
+}
+
+// FprintCompletion writes to w the completion script of the flags of the
+// fields for the given shell: bash, zsh or fish. The script completes the
+// program named after the running binary.
+func FprintCompletion(w io.Writer, shell string, fields []*Field) error {
+	flags := completionFlags(fields)
+	name := programName()
+
+	switch shell {
+	case "bash":
+		fprintBashCompletion(w, name, flags)
+	case "zsh":
+		fprintZshCompletion(w, name, flags)
+	case "fish":
+		fprintFishCompletion(w, name, flags)
+	default:
+		return fmt.Errorf("unsupported shell %q, expected bash, zsh or fish", shell)
+	}
+
+	return nil
+}
+
+// programName returns the name of the running binary.
+func programName() string {
+	return filepath.Base(os.Args[0])
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// completionFunction returns the name of the completion function of a program.
+func completionFunction(name string) string {
+	return "_" + nonIdentifierChars.ReplaceAllString(name, "_")
+}
+
+// fprintBashCompletion writes a bash completion script. The values can be
+// given either as --flag=value or --flag value, and the `=` is a separate
+// word for bash.
+func fprintBashCompletion(w io.Writer, name string, flags []completionFlag) {
+	function := completionFunction(name)
+
+	var names []string
+	for _, flag := range flags {
+		names = append(names, "--"+flag.name)
+	}
+
+	fmt.Fprintf(w, "# bash completion for %s\n", name)
+	fmt.Fprintf(w, "%s() {\n", function)
+	fmt.Fprintf(w, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" flag=\"\"\n")
+	fmt.Fprintf(w, "\tif [[ \"$cur\" == \"=\" ]]; then\n")
+	fmt.Fprintf(w, "\t\tflag=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(w, "\t\tcur=\"\"\n")
+	fmt.Fprintf(w, "\telif [[ \"${COMP_WORDS[COMP_CWORD-1]}\" == \"=\" ]]; then\n")
+	fmt.Fprintf(w, "\t\tflag=\"${COMP_WORDS[COMP_CWORD-2]}\"\n")
+	fmt.Fprintf(w, "\telif [[ \"$cur\" != -* ]]; then\n")
+	fmt.Fprintf(w, "\t\tflag=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(w, "\tfi\n")
+	fmt.Fprintf(w, "\t[[ \"$flag\" == --* ]] || flag=\"\"\n\n")
+
+	fmt.Fprintf(w, "\tcase \"$flag\" in\n")
+	for _, flag := range flags {
+		switch {
+		case flag.kind == CompleteFile:
+			fmt.Fprintf(w, "\t--%s)\n\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n\t\treturn\n\t\t;;\n", flag.name)
+		case flag.kind == CompleteDir:
+			fmt.Fprintf(w, "\t--%s)\n\t\tCOMPREPLY=($(compgen -d -- \"$cur\"))\n\t\treturn\n\t\t;;\n", flag.name)
+		case len(flag.values) != 0:
+			fmt.Fprintf(w, "\t--%s)\n\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n\t\treturn\n\t\t;;\n", flag.name, strings.Join(flag.values, " "))
+		}
+	}
+	fmt.Fprintf(w, "\tesac\n\n")
+
+	fmt.Fprintf(w, "\tif [[ -z \"$flag\" ]]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(names, " "))
+	fmt.Fprintf(w, "\tfi\n")
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "complete -o default -F %s %s\n", function, name)
+}
+
+// fprintZshCompletion writes a zsh completion script, which can either be
+// installed in the fpath or sourced.
+func fprintZshCompletion(w io.Writer, name string, flags []completionFlag) {
+	function := completionFunction(name)
+
+	fmt.Fprintf(w, "#compdef %s\n\n", name)
+	fmt.Fprintf(w, "%s() {\n", function)
+	fmt.Fprintf(w, "\t_arguments")
+	for _, flag := range flags {
+		var action string
+		switch {
+		case flag.kind == CompleteFile:
+			action = "_files"
+		case flag.kind == CompleteDir:
+			action = "_files -/"
+		case len(flag.values) != 0:
+			action = "(" + strings.Join(flag.values, " ") + ")"
+		}
+
+		var desc string
+		if flag.desc != "" {
+			desc = "[" + zshEscape(flag.desc) + "]"
+		}
+
+		spec := fmt.Sprintf("--%s=%s:%s:%s", flag.name, desc, zshEscape(flag.name), action)
+		fmt.Fprintf(w, " \\\n\t\t'%s'", strings.ReplaceAll(spec, "'", `'\''`))
+	}
+	fmt.Fprintf(w, "\n}\n\n")
+
+	fmt.Fprintf(w, "if [ \"$funcstack[1]\" = \"%s\" ]; then\n", function)
+	fmt.Fprintf(w, "\t%s \"$@\"\n", function)
+	fmt.Fprintf(w, "else\n")
+	fmt.Fprintf(w, "\tcompdef %s %s\n", function, name)
+	fmt.Fprintf(w, "fi\n")
+}
+
+// zshEscape escapes the characters of a text having a meaning in the
+// specifications of _arguments.
+func zshEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`, "\n", " ").Replace(s)
+}
+
+// fprintFishCompletion writes a fish completion script.
+func fprintFishCompletion(w io.Writer, name string, flags []completionFlag) {
+	fmt.Fprintf(w, "# fish completion for %s\n", name)
+	for _, flag := range flags {
+		var options string
+		switch {
+		case flag.kind == CompleteFile:
+			options = " -r -F"
+		case flag.kind == CompleteDir:
+			options = " -x -a '(__fish_complete_directories)'"
+		case len(flag.values) != 0:
+			options = " -x -a " + fishQuote(strings.Join(flag.values, " "))
+		default:
+			options = " -x"
+		}
+
+		if flag.desc != "" {
+			options += " -d " + fishQuote(flag.desc)
+		}
+
+		fmt.Fprintf(w, "complete -c %s -l %s%s\n", fishQuote(name), fishQuote(flag.name), options)
+	}
+}
+
+// fishQuote quotes a text for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", " ").Replace(s) + "'"
+}
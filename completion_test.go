@@ -0,0 +1,128 @@
+package zconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFprintCompletion(t *testing.T) {
+	var s struct {
+		Verbose *bool  `key:"verbose" description:"be [very] verbose"`
+		Cert    string `key:"tls.cert-file" complete:"file"`
+		Data    string `key:"data-dir" complete:"dir" default:"."`
+		Addr    string `key:"addr" flag:"listen" default:":80"`
+	}
+	fields := usageFields(t, &s)
+
+	for shell, expected := range map[string][]string{
+		"bash": {
+			"\t--verbose)\n\t\tCOMPREPLY=($(compgen -W \"true false\" -- \"$cur\"))\n",
+			"\t--tls.cert-file)\n\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n",
+			"\t--data-dir)\n\t\tCOMPREPLY=($(compgen -d -- \"$cur\"))\n",
+			`COMPREPLY=($(compgen -W "--listen --data-dir --tls.cert-file --verbose --help --completion --config --dotenv" -- "$cur"))`,
+			"complete -o default -F ",
+		},
+		"zsh": {
+			`'--verbose=[be \[very\] verbose]:verbose:(true false)'`,
+			`'--tls.cert-file=:tls.cert-file:_files'`,
+			`'--data-dir=:data-dir:_files -/'`,
+			`'--listen=:listen:'`,
+			"\tcompdef ",
+		},
+		"fish": {
+			"-l 'verbose' -x -a 'true false' -d 'be [very] verbose'\n",
+			"-l 'tls.cert-file' -r -F\n",
+			"-l 'data-dir' -x -a '(__fish_complete_directories)'\n",
+			"-l 'listen' -x\n",
+		},
+	} {
+		var output bytes.Buffer
+		err := FprintCompletion(&output, shell, fields)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", shell, err)
+		}
+
+		for _, e := range expected {
+			if !strings.Contains(output.String(), e) {
+				t.Errorf("%s: expected completion to contain %q, got:\n%s", shell, e, output.String())
+			}
+		}
+	}
+
+	err := FprintCompletion(new(bytes.Buffer), "tcsh", fields)
+	if err == nil {
+		t.Error("expected an error for an unsupported shell, got nil")
+	}
+}
+
+func TestProcessorCompletion(t *testing.T) {
+	oldArgs := Args
+	t.Cleanup(func() {
+		Args = oldArgs
+	})
+	Args = &ArgsProvider{Args: map[string]string{"completion": "fish"}}
+
+	var s struct {
+		Addr string `key:"addr"`
+	}
+
+	var output bytes.Buffer
+	p := NewProcessor()
+	p.Output = &output
+	p.ReturnOnHelp = true
+	p.Completion = true
+
+	err := p.Process(context.Background(), &s)
+	if !errors.Is(err, ErrCompletionRequested) {
+		t.Fatalf("expected ErrCompletionRequested, got %v", err)
+	}
+
+	if !strings.Contains(output.String(), "-l 'addr' -x\n") {
+		t.Errorf("unexpected completion:\n%s", output.String())
+	}
+}
+
+func TestProcessorCompletionFlag(t *testing.T) {
+	oldArgs := Args
+	t.Cleanup(func() {
+		Args = oldArgs
+	})
+	Args = &ArgsProvider{Args: map[string]string{"completion": "auto"}}
+
+	var s struct {
+		Completion string `key:"completion"`
+	}
+
+	var repository Repository
+	repository.AddProviders(Args)
+	repository.AddParsers(ParseString)
+
+	// Without completion, the flag is free.
+	err := NewProcessor(repository.Hook).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Completion != "auto" {
+		t.Errorf("expected the field to be configured, got %q", s.Completion)
+	}
+
+	p := NewProcessor(repository.Hook)
+	p.Completion = true
+	err = p.Process(context.Background(), &s)
+	if err == nil || !strings.Contains(err.Error(), "field $.Completion (key completion) uses the reserved flag --completion") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestShellEscape(t *testing.T) {
+	if escaped := zshEscape(`a [b]: c\d`); escaped != `a \[b\]\: c\\d` {
+		t.Errorf("unexpected zsh escaping: %s", escaped)
+	}
+
+	if quoted := fishQuote(`it's a\b`); quoted != `'it\'s a\\b'` {
+		t.Errorf("unexpected fish quoting: %s", quoted)
+	}
+}
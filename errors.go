@@ -0,0 +1,150 @@
+package zconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrHelpRequested is returned by a Processor with ReturnOnHelp set once the
+// usage message is written, when the --help flag is passed.
+var ErrHelpRequested = errors.New("help requested")
+
+// ErrCompletionRequested is returned by a Processor with ReturnOnHelp set once
+// the completion script is written, when the --completion flag is passed.
+var ErrCompletionRequested = errors.New("completion requested")
+
+// Errors is a list of errors returned at once, e.g. by a Processor collecting
+// the errors of all the fields. It can be inspected with errors.Is and
+// errors.As, which match any of the errors of the list.
+type Errors []error
+
+// Error returns the messages of the errors, one per line.
+func (e Errors) Error() string {
+	var messages = make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the errors of the list.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Is reports whether one of the errors of the list matches the target, for
+// errors.Is before Go 1.20 which doesn't look through Unwrap.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the list matching the target, for errors.As
+// before Go 1.20 which doesn't look through Unwrap.
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingKeyError is returned when no provider has a value for the key of a
+// field without default value.
+type MissingKeyError struct {
+	// Path of the field, e.g. `$.Server.Addr`.
+	Path string
+
+	// Configuration key of the field, e.g. `server.addr`.
+	Key string
+}
+
+func (e *MissingKeyError) Error() string {
+	return fmt.Sprintf("missing key %s", e.Key)
+}
+
+// ParseError is returned when the value of a field cannot be parsed into the
+// type of the field.
+type ParseError struct {
+	// Path of the field, e.g. `$.Server.Addr`.
+	Path string
+
+	// Configuration key of the field, e.g. `server.addr`.
+	Key string
+
+	// Provider of the value, e.g. `env` or `default`.
+	Provider string
+
+	// Raw value as returned by the provider.
+	Raw interface{}
+
+	// Type of the field.
+	Type reflect.Type
+
+	// Secret is true for the secret fields, for which the error message
+	// leaves out the message of the parser, as it may contain the value. It
+	// remains available through Err.
+	Secret bool
+
+	// Err is the error returned by the parser.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Secret {
+		return fmt.Sprintf("parsing value for key %s: invalid value for type %s", e.Key, e.Type)
+	}
+	return fmt.Sprintf("parsing value for key %s: %s", e.Key, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned when the value of a field violates one of the
+// rules of its `validate` tag. The value itself is not part of the error, so
+// the secrets are not leaked.
+type ValidationError struct {
+	// Path of the field, e.g. `$.Server.Port`.
+	Path string
+
+	// Configuration key of the field, e.g. `server.port`.
+	Key string
+
+	// Provider of the value, e.g. `env` or `default`.
+	Provider string
+
+	// Rule violated by the value, e.g. `min=1`.
+	Rule string
+
+	// Err describes the violation.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validating value for key %s from %s: rule %s: %s", e.Key, e.Provider, e.Rule, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// CycleError is returned when the fields of a struct depend on each other
+// through injections.
+type CycleError struct {
+	// Cycle is the list of paths of the fields forming the cycle, the first
+	// one depending on the second one, and so on until the last one that
+	// depends on the first one.
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("cycle detected: %s", strings.Join(e.Cycle, " -> "))
+}
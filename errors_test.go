@@ -0,0 +1,87 @@
+package zconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	errs := Errors{
+		fmt.Errorf("first: %w", io.EOF),
+		fmt.Errorf("second: %w", &os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist}),
+	}
+
+	if errs.Error() != "first: EOF\nsecond: open foo: file does not exist" {
+		t.Errorf("unexpected message: %q", errs.Error())
+	}
+
+	if !errors.Is(errs, io.EOF) || !errors.Is(errs, os.ErrNotExist) {
+		t.Error("expected the errors to match their causes")
+	}
+
+	var perr *os.PathError
+	if !errors.As(errs, &perr) || perr.Path != "foo" {
+		t.Errorf("expected to extract the path error, got %v", perr)
+	}
+
+	// The methods used by errors.Is and errors.As before Go 1.20.
+	if !errs.Is(os.ErrNotExist) || errs.Is(os.ErrExist) {
+		t.Error("expected Is to match the causes only")
+	}
+
+	perr = nil
+	if !errs.As(&perr) || perr.Path != "foo" {
+		t.Errorf("expected As to extract the path error, got %v", perr)
+	}
+
+	var nerr *strconv.NumError
+	if errs.As(&nerr) {
+		t.Errorf("unexpected number error %v", nerr)
+	}
+}
+
+func TestConfigurationErrors(t *testing.T) {
+	var s struct {
+		Server struct {
+			Addr    string `key:"addr"`
+			Workers int    `key:"workers"`
+		} `key:"server"`
+	}
+
+	var repository Repository
+	repository.AddProviders(TestProvider{"test", map[string]string{"server.workers": "many"}})
+	repository.AddParsers(ParseString)
+
+	p := NewProcessor(repository.Hook)
+	p.CollectErrors = true
+
+	err := p.Process(context.Background(), &s)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	var merr *MissingKeyError
+	if !errors.As(err, &merr) {
+		t.Fatalf("expected a missing key error, got: %v", err)
+	}
+	if merr.Path != "$.Server.Addr" || merr.Key != "server.addr" {
+		t.Errorf("unexpected missing key error: %+v", merr)
+	}
+
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a parse error, got: %v", err)
+	}
+	if perr.Path != "$.Server.Workers" || perr.Key != "server.workers" || perr.Provider != "test" || perr.Raw != "many" || perr.Type != reflect.TypeOf(0) {
+		t.Errorf("unexpected parse error: %+v", perr)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected the parse error to wrap the parser's error, got: %v", perr.Err)
+	}
+}
@@ -3,6 +3,7 @@ package zconfig
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 )
 
 const (
@@ -11,8 +12,18 @@ const (
 	TagKey         = "key"
 	TagDefault     = "default"
 	TagDescription = "description"
+	TagEnv         = "env"
+	TagFlag        = "flag"
+	TagAlias       = "alias"
+	TagDeprecated  = "deprecated"
+	TagGroup       = "group"
+	TagSecret      = "secret"
 )
 
+// Redacted replaces the values of the secret fields in the help and error
+// messages.
+const Redacted = "******"
+
 type Field struct {
 	Value     reflect.Value
 	Path      string
@@ -26,6 +37,15 @@ type Field struct {
 	Provider         string
 	Configurable     bool
 	ConfigurationKey string
+
+	// Alternative configuration keys of the field, from the `alias` and
+	// `deprecated` tags, and the one the value was retrieved with, if any.
+	AliasKeys      []string
+	DeprecatedKeys []string
+	Alias          string
+
+	// Whether the field is a nil pointer allocated when walking the struct.
+	allocated bool
 }
 
 func (f *Field) Inject(s *Field) (err error) {
@@ -41,11 +61,60 @@ func (f *Field) Inject(s *Field) (err error) {
 	return nil
 }
 
+// IsSecret returns true if the value of the field must not be displayed, as
+// set by the `secret` tag or by a field of type Secret.
+func (f *Field) IsSecret() bool {
+	if isSecretType(f.Value.Type()) {
+		return true
+	}
+	secret, _ := strconv.ParseBool(f.Tags.Get(TagSecret))
+	return secret
+}
+
+// isDeprecatedKey returns true if the key is one of the deprecated keys of
+// the field.
+func (f *Field) isDeprecatedKey(key string) bool {
+	for _, deprecated := range f.DeprecatedKeys {
+		if key == deprecated {
+			return true
+		}
+	}
+	return false
+}
+
+// Group returns the name under which the field is listed in the usage
+// message: the `group` tag of the field or of its closest ancestor having one,
+// or else the description of its closest described ancestor. It is empty for
+// the fields outside any group.
+func (f *Field) Group() string {
+	for p := f; p != nil; p = p.Parent {
+		if group, ok := p.Tags.Lookup(TagGroup); ok {
+			return group
+		}
+	}
+	for p := f.Parent; p != nil; p = p.Parent {
+		if desc, ok := p.Tags.Lookup(TagDescription); ok {
+			return desc
+		}
+	}
+	return ""
+}
+
 func (f *Field) IsLeaf() bool {
 	if _, ok := f.Tags.Lookup(TagInject); ok {
 		return true
 	}
 
+	// A Secret is configured as a whole.
+	if isSecretType(f.Value.Type()) {
+		return true
+	}
+
+	// A slice of structs is configured element by element.
+	if f.isStructSlice() {
+		return false
+	}
+
 	// The field is a leaf if it is a type different than a struct or a
 	// pointer to a struct.
 	if f.Value.Kind() != reflect.Ptr {
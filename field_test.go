@@ -131,3 +131,45 @@ func TestField_Inject(t *testing.T) {
 		})
 	}
 }
+
+func TestField_Group(t *testing.T) {
+	var s struct {
+		Addr     string `key:"addr"`
+		Database struct {
+			DSN  string `key:"dsn"`
+			Pool struct {
+				Size int `key:"size"`
+			} `key:"pool"`
+		} `key:"database" description:"Database connection"`
+		Cache struct {
+			TTL  int    `key:"ttl"`
+			Addr string `key:"addr" group:"Network"`
+		} `key:"cache" group:"Cache" description:"cache settings"`
+	}
+
+	root, err := walk(reflect.ValueOf(&s), reflect.StructField{}, nil)
+	if err != nil {
+		t.Fatalf("walking service: %s", err)
+	}
+	fields, err := resolve(root)
+	if err != nil {
+		t.Fatalf("resolving service: %s", err)
+	}
+
+	expected := map[string]string{
+		"$.Addr":               "",
+		"$.Database.DSN":       "Database connection",
+		"$.Database.Pool.Size": "Database connection",
+		"$.Cache.TTL":          "Cache",
+		"$.Cache.Addr":         "Network",
+	}
+	for _, f := range fields {
+		group, ok := expected[f.Path]
+		if !ok {
+			continue
+		}
+		if f.Group() != group {
+			t.Errorf("field %s: expected group %q, got %q", f.Path, group, f.Group())
+		}
+	}
+}
@@ -2,15 +2,26 @@ module github.com/synthesio/zconfig/v2
 
 go 1.18
 
-require github.com/hchargois/flexwriter v1.2.0
+require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/hashicorp/hcl/v2 v2.19.1
+	github.com/hchargois/flexwriter v1.2.0
+	github.com/zclconf/go-cty v1.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/MichaelMure/go-term-text v0.3.1 // indirect
+	github.com/agext/levenshtein v1.2.1 // indirect
+	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
+	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
 	github.com/fatih/color v1.18.0 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/term v0.28.0 // indirect
+	golang.org/x/text v0.11.0 // indirect
 )
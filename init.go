@@ -19,6 +19,11 @@ var typeInitializable = reflect.TypeOf((*Initializable)(nil)).Elem()
 var typeInitializableDeprecated = reflect.TypeOf((*initializableDeprecated)(nil)).Elem()
 
 func Initialize(ctx context.Context, field *Field) error {
+	// Unset optional field, nothing to initialize.
+	if field.isUnset() {
+		return nil
+	}
+
 	// Not initializable, nothing to do.
 	if field.Value.Type().Implements(typeInitializable) {
 
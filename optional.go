@@ -0,0 +1,97 @@
+package zconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+const TagOptional = "optional"
+
+// isOptional returns true if the field is tagged `optional:"true"`.
+func (f *Field) isOptional() bool {
+	optional, _ := strconv.ParseBool(f.Tags.Get(TagOptional))
+	return optional
+}
+
+// hasOptionalAncestor returns true if one of the ancestors of the field is
+// optional, in which case the field is checked along with it.
+func (f *Field) hasOptionalAncestor() bool {
+	for p := f.Parent; p != nil; p = p.Parent {
+		if p.isOptional() {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnset returns true if the field, or one of its ancestors, is a nil
+// pointer, e.g. an optional field left unset.
+func (f *Field) isUnset() bool {
+	for p := f; p != nil; p = p.Parent {
+		if p.Value.Kind() == reflect.Ptr && p.Value.IsNil() {
+			return true
+		}
+	}
+	return false
+}
+
+// inUnsetStruct returns true if one of the ancestors of the field is a nil
+// pointer.
+func (f *Field) inUnsetStruct() bool {
+	return f.Parent != nil && f.Parent.isUnset()
+}
+
+// unset sets back to nil a pointer allocated when walking the struct.
+func (f *Field) unset() {
+	if f.allocated {
+		f.Value.Set(reflect.Zero(f.Value.Type()))
+	}
+}
+
+// checkOptional checks an optional struct once its descendants are
+// configured: it is unset if none of their keys is provided, otherwise its
+// descendants must all be configured.
+func checkOptional(f *Field) error {
+	var provided bool
+	var missing []*Field
+
+	var visit func(p *Field, nested bool)
+	visit = func(p *Field, nested bool) {
+		for _, c := range p.Children {
+			provided = provided || c.isProvided()
+
+			// The optional descendants are checked on their own, and
+			// the conditional ones by CheckRequirements.
+			tolerated := nested || c.isOptional() || c.isConditional()
+			if c.Configurable && c.Provider == "" && !tolerated {
+				missing = append(missing, c)
+			}
+
+			visit(c, nested || c.isOptional())
+		}
+	}
+	visit(f, false)
+
+	if !provided {
+		f.unset()
+		return nil
+	}
+
+	var errs Errors
+	for _, c := range missing {
+		errs = append(errs, fmt.Errorf("configuring field %s: %w", c.Path, &MissingKeyError{
+			Path: c.Path,
+			Key:  c.ConfigurationKey,
+		}))
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
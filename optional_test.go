@@ -0,0 +1,143 @@
+package zconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type optionalTest struct {
+	Addr        string `key:"addr"`
+	Timeout     int    `key:"timeout" default:"30"`
+	Initialized bool
+	Validated   bool
+}
+
+func (o *optionalTest) Init(ctx context.Context) error {
+	o.Initialized = true
+	return nil
+}
+
+func (o *optionalTest) Validate(ctx context.Context) error {
+	o.Validated = true
+	return nil
+}
+
+type optionalService struct {
+	Port    *int          `key:"port" optional:"true"`
+	Name    string        `key:"name" optional:"true"`
+	Proxy   *optionalTest `key:"proxy" optional:"true"`
+	Metrics *struct {
+		Addr string `key:"addr"`
+		Push *struct {
+			Gateway string `key:"gateway"`
+		} `key:"push" optional:"true"`
+	} `key:"metrics" optional:"true"`
+}
+
+func processOptional(t *testing.T, s interface{}, values map[string]string) error {
+	t.Helper()
+
+	var repository Repository
+	repository.AddProviders(TestProvider{name: "test", values: values})
+	repository.AddParsers(ParseString)
+
+	return NewProcessor(repository.Hook, CheckRequirements, Validate, Initialize).Process(context.Background(), s)
+}
+
+func TestOptional(t *testing.T) {
+	t.Run("unset", func(t *testing.T) {
+		var s optionalService
+		err := processOptional(t, &s, map[string]string{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if s.Port != nil || s.Name != "" || s.Proxy != nil || s.Metrics != nil {
+			t.Errorf("expected the optional fields to be unset, got %+v", s)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		var s optionalService
+		err := processOptional(t, &s, map[string]string{
+			"port":                 "8080",
+			"name":                 "api",
+			"proxy.addr":           "proxy:3128",
+			"metrics.addr":         ":9090",
+			"metrics.push.gateway": "pushgateway:9091",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if s.Port == nil || *s.Port != 8080 || s.Name != "api" {
+			t.Errorf("unexpected optional leaves: %v %s", s.Port, s.Name)
+		}
+		if s.Proxy == nil || s.Proxy.Addr != "proxy:3128" || s.Proxy.Timeout != 30 {
+			t.Fatalf("unexpected proxy: %+v", s.Proxy)
+		}
+		if !s.Proxy.Validated || !s.Proxy.Initialized {
+			t.Errorf("expected the proxy to be validated and initialized")
+		}
+		if s.Metrics == nil || s.Metrics.Push == nil || s.Metrics.Push.Gateway != "pushgateway:9091" {
+			t.Errorf("unexpected metrics: %+v", s.Metrics)
+		}
+	})
+
+	t.Run("nested unset", func(t *testing.T) {
+		var s optionalService
+		err := processOptional(t, &s, map[string]string{"metrics.addr": ":9090"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if s.Metrics == nil || s.Metrics.Addr != ":9090" || s.Metrics.Push != nil {
+			t.Errorf("unexpected metrics: %+v", s.Metrics)
+		}
+	})
+
+	t.Run("not initialized", func(t *testing.T) {
+		proxy := new(optionalTest)
+		s := optionalService{Proxy: proxy}
+		err := processOptional(t, &s, map[string]string{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if s.Proxy != proxy {
+			t.Error("expected a pointer set beforehand to be kept")
+		}
+	})
+
+	t.Run("partially set", func(t *testing.T) {
+		var repository Repository
+		repository.AddProviders(TestProvider{name: "test", values: map[string]string{"proxy.timeout": "10", "metrics.push.gateway": "pushgateway:9091"}})
+		repository.AddParsers(ParseString)
+
+		processor := NewProcessor(repository.Hook)
+		processor.CollectErrors = true
+
+		var s optionalService
+		err := processor.Process(context.Background(), &s)
+
+		var errs Errors
+		if !errors.As(err, &errs) || len(errs) != 2 {
+			t.Fatalf("expected two errors, got %v", err)
+		}
+
+		// The fields are not configured in a set order.
+		var keys = make(map[string]bool)
+		for _, err := range errs {
+			var missing *MissingKeyError
+			if errors.As(err, &missing) {
+				keys[missing.Key] = true
+			}
+		}
+		for _, key := range []string{"proxy.addr", "metrics.addr"} {
+			if !keys[key] {
+				t.Errorf("expected the missing key %s, got %v", key, errs)
+			}
+		}
+	})
+}
@@ -2,6 +2,9 @@ package zconfig
 
 import (
 	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -144,3 +147,113 @@ func ParseString(raw, res interface{}) (err error) {
 
 	return nil
 }
+
+// ParseNative converts the native values returned by structured providers
+// (e.g. the YAMLProvider, JSONProvider or TOMLProvider) to the expected type.
+// Values that are directly assignable to the expected type are used as is.
+// Otherwise, scalars are formatted and handed to ParseString, so the same
+// conversions apply (text marshalers such as time.Time are formatted as text),
+// while lists and mappings are parsed element by element into slices and maps.
+func ParseNative(raw, res interface{}) (err error) {
+	if _, ok := raw.(string); ok {
+		// Let ParseString handle it.
+		return ErrNotParseable
+	}
+
+	v := reflect.ValueOf(res)
+	if raw != nil && v.Kind() == reflect.Ptr && reflect.TypeOf(raw).AssignableTo(v.Type().Elem()) {
+		v.Elem().Set(reflect.ValueOf(raw))
+		return nil
+	}
+
+	switch raw := raw.(type) {
+	case bool:
+		return ParseString(strconv.FormatBool(raw), res)
+	case int:
+		return ParseString(strconv.Itoa(raw), res)
+	case int64:
+		return ParseString(strconv.FormatInt(raw, 10), res)
+	case uint64:
+		return ParseString(strconv.FormatUint(raw, 10), res)
+	case float64:
+		return ParseString(strconv.FormatFloat(raw, 'f', -1, 64), res)
+	case json.Number:
+		return ParseString(raw.String(), res)
+	case []interface{}:
+		return parseList(raw, res)
+	case map[string]interface{}:
+		return parseMap(raw, res)
+	case encoding.TextMarshaler:
+		var text []byte
+		text, err = raw.MarshalText()
+		if err != nil {
+			return err
+		}
+		return ParseString(string(text), res)
+	default:
+		return ErrNotParseable
+	}
+}
+
+// parseList parses each element of a list into a new slice of the type
+// pointed to by res.
+func parseList(raw []interface{}, res interface{}) (err error) {
+	v := reflect.ValueOf(res)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return ErrNotParseable
+	}
+
+	slice := reflect.MakeSlice(v.Elem().Type(), len(raw), len(raw))
+	for i, elem := range raw {
+		err = parseElement(elem, slice.Index(i).Addr().Interface())
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	v.Elem().Set(slice)
+
+	return nil
+}
+
+// parseMap parses each entry of a mapping into a new map of the type pointed
+// to by res.
+func parseMap(raw map[string]interface{}, res interface{}) (err error) {
+	v := reflect.ValueOf(res)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Map {
+		return ErrNotParseable
+	}
+
+	typ := v.Elem().Type()
+	m := reflect.MakeMapWithSize(typ, len(raw))
+	for key, elem := range raw {
+		k := reflect.New(typ.Key())
+		err = parseElement(key, k.Interface())
+		if err != nil {
+			return fmt.Errorf("key %s: %w", key, err)
+		}
+
+		e := reflect.New(typ.Elem())
+		err = parseElement(elem, e.Interface())
+		if err != nil {
+			return fmt.Errorf("entry %s: %w", key, err)
+		}
+
+		m.SetMapIndex(k.Elem(), e.Elem())
+	}
+	v.Elem().Set(m)
+
+	return nil
+}
+
+// parseElement parses a single element of a native list using the built-in
+// parsers.
+func parseElement(raw, res interface{}) (err error) {
+	for _, p := range []Parser{ParseString, ParseNative} {
+		err = p(raw, res)
+		if err == ErrNotParseable {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("no parser for type %T", res)
+}
@@ -1,6 +1,7 @@
 package zconfig
 
 import (
+	"encoding/json"
 	"reflect"
 	"regexp"
 	"testing"
@@ -97,3 +98,72 @@ func TestParseString(t *testing.T) {
 		}
 	}
 }
+
+func TestParseNative(t *testing.T) {
+	for _, c := range []struct {
+		raw interface{}
+		res interface{}
+		err bool
+	}{
+		// Left to ParseString
+		{raw: "foo", res: "", err: true},
+
+		// Scalars
+		{raw: 1, res: int(1), err: false},
+		{raw: 1, res: "1", err: false},
+		{raw: int64(1), res: int8(1), err: false},
+		{raw: uint64(1), res: uint(1), err: false},
+		{raw: 1.5, res: float64(1.5), err: false},
+		{raw: 1.5, res: int(1), err: true},
+		{raw: float64(1000000), res: int(1000000), err: false},
+		{raw: json.Number("9007199254740993"), res: int64(9007199254740993), err: false},
+		{raw: json.Number("1.5"), res: float32(1.5), err: false},
+		{raw: json.Number("1.5"), res: int(1), err: true},
+		{raw: 300, res: int8(0), err: true},
+		{raw: true, res: true, err: false},
+		{raw: true, res: 0, err: true},
+
+		// Lists
+		{raw: []interface{}{"foo", "bar"}, res: []string{"foo", "bar"}, err: false},
+		{raw: []interface{}{10, 20}, res: []int{10, 20}, err: false},
+		{raw: []interface{}{10, "20"}, res: []int64{10, 20}, err: false},
+		{raw: []interface{}{"1s", "1m"}, res: []time.Duration{time.Second, time.Minute}, err: false},
+		{raw: []interface{}{"foo"}, res: []int{}, err: true},
+		{raw: []interface{}{"foo"}, res: "", err: true},
+
+		// Mappings
+		{raw: map[string]interface{}{"a": "foo"}, res: map[string]string{"a": "foo"}, err: false},
+		{raw: map[string]interface{}{"a": float64(1)}, res: map[string]int{"a": 1}, err: false},
+		{raw: map[string]interface{}{"1": true}, res: map[int]bool{1: true}, err: false},
+		{raw: map[string]interface{}{"a": "foo"}, res: map[int]string{}, err: true},
+		{raw: map[string]interface{}{"a": "foo"}, res: map[string]int{}, err: true},
+
+		// Passthrough
+		{raw: float64(1), res: float64(1), err: false},
+		{raw: []interface{}{"foo", 1}, res: []interface{}{"foo", 1}, err: false},
+		{raw: map[string]interface{}{"a": 1}, res: map[string]interface{}{"a": 1}, err: false},
+		{raw: map[string]interface{}{"a": 1}, res: "", err: true},
+	} {
+		var typ = reflect.TypeOf(c.res)
+		var res = reflect.New(typ).Interface()
+
+		err := ParseNative(c.raw, res)
+		if (err != nil) != c.err {
+			if c.err {
+				t.Errorf("ParseNative(%+v): should fail", c.raw)
+			} else {
+				t.Errorf("ParseNative(%+v): unexpected error %v", c.raw, err)
+			}
+			continue
+		}
+
+		if c.err {
+			continue
+		}
+
+		res = reflect.ValueOf(res).Elem().Interface()
+		if !reflect.DeepEqual(res, c.res) {
+			t.Errorf("ParseNative(%+v): wanted %+v, got %+v", c.raw, c.res, res)
+		}
+	}
+}
@@ -3,13 +3,12 @@ package zconfig
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"sort"
 	"strings"
 	"unicode"
-
-	"github.com/hchargois/flexwriter"
 )
 
 // A Processor handle the service processing and execute hooks on the resulting
@@ -29,6 +28,56 @@ type Processor struct {
 	// If UsageVal is unset, then Usage is used. If Usage is unset too, then
 	// DefaultUsageVal is used.
 	UsageVal func(value string, fields []*Field)
+
+	// UsageWriter is like UsageVal, but writes the usage message to the given
+	// writer, which is Output. It takes precedence over UsageVal and Usage. If
+	// none of them is set, FprintUsageVal is used, with the values of
+	// --help=values retrieved by Lookup.
+	UsageWriter func(w io.Writer, value string, fields []*Field)
+
+	// Output is the writer the usage message is written to when help is
+	// requested, and the completion script when a completion is requested
+	// with the --completion flag. If unset, the standard output is used.
+	Output io.Writer
+
+	// Completion makes Process write the completion script of the fields for
+	// the shell given by the --completion flag, e.g. --completion=bash, to
+	// Output and exit. The flag is then reserved and cannot be used by the
+	// fields. The default processor enables it.
+	Completion bool
+
+	// ReturnOnHelp makes Process return ErrHelpRequested once the usage
+	// message is written instead of exiting the program, so the caller can
+	// clean up before exiting or test the help output. Likewise, it returns
+	// ErrCompletionRequested once the completion script is written.
+	ReturnOnHelp bool
+
+	// CollectErrors makes each hook run on all the fields even if it fails
+	// on some of them, so all the missing or invalid keys are reported at
+	// once. The errors are returned as Errors, and the next hooks (e.g.
+	// Initialize) are not executed.
+	CollectErrors bool
+
+	// Exists reports whether a configuration key has a value. It is used to
+	// count the elements of the slices of structs, e.g. `backends.0.addr`,
+	// usually set to the Exists method of the repository of the hooks. If
+	// unset, the slices of structs are configured as a whole, like the other
+	// leaves. The default processor uses the default repository.
+	Exists func(key string) (bool, error)
+
+	// Setup is called by Process with the fields once they are checked, and
+	// before the help message is handled, e.g. to load providers depending
+	// on the command line and make sure the fields don't use the flags it
+	// reads. Its error is returned by Process. The default processor loads
+	// the configuration files into the default repository.
+	Setup func(fields []*Field) error
+
+	// Lookup returns the raw value a field would be configured with and the
+	// name of the provider supplying it, as displayed by --help=values with
+	// the default usage message, usually the LookupField method of the
+	// repository of the hooks. If unset, the values are not displayed. The
+	// default processor uses the default repository.
+	Lookup func(f *Field) (raw interface{}, provider string, found bool, err error)
 }
 
 func NewProcessor(hooks ...Hook) *Processor {
@@ -60,36 +109,126 @@ func (p *Processor) Process(ctx context.Context, s interface{}) error {
 
 	mark(root, "")
 
+	// Without Exists, the slices of structs are configured as a whole.
+	if p.Exists == nil {
+		leafSlices(root, "")
+
+		fields, err = resolve(root)
+		if err != nil {
+			return fmt.Errorf("resolving struct: %w", err)
+		}
+	}
+
+	err = checkCollisions(fields)
+	if err != nil {
+		return fmt.Errorf("checking names: %w", err)
+	}
+
+	err = checkValidationTags(fields)
+	if err != nil {
+		return fmt.Errorf("checking tags: %w", err)
+	}
+
+	if p.Completion {
+		err = checkReserved(fields, []string{"completion"}, nil)
+		if err != nil {
+			return fmt.Errorf("checking names: %w", err)
+		}
+	}
+
+	if p.Setup != nil {
+		err = p.Setup(fields)
+		if err != nil {
+			return fmt.Errorf("setting up: %w", err)
+		}
+	}
+
 	if rawVal, ok, _ := Args.Retrieve("help"); ok {
 		// we know rawVal is a string since it's coming from an ArgsProvider.
 		val := rawVal.(string)
 
-		var usage func(string, []*Field)
+		var usage func(io.Writer, string, []*Field)
 		switch {
+		case p.UsageWriter != nil:
+			usage = p.UsageWriter
 		case p.UsageVal != nil:
-			usage = p.UsageVal
+			usage = func(_ io.Writer, val string, fields []*Field) { p.UsageVal(val, fields) }
 		case p.Usage != nil:
-			usage = func(_ string, fields []*Field) { p.Usage(fields) }
+			usage = func(_ io.Writer, _ string, fields []*Field) { p.Usage(fields) }
 		default:
-			usage = DefaultUsageVal
+			usage = func(w io.Writer, val string, fields []*Field) { fprintUsageVal(w, val, fields, p.Lookup) }
 		}
 
-		usage(val, fields)
+		usage(p.output(), val, fields)
+		if p.ReturnOnHelp {
+			return ErrHelpRequested
+		}
 		os.Exit(0)
 	}
 
+	if rawShell, ok, _ := Args.Retrieve("completion"); ok && p.Completion {
+		err := FprintCompletion(p.output(), rawShell.(string), fields)
+		if err != nil {
+			return fmt.Errorf("generating completion: %w", err)
+		}
+
+		if p.ReturnOnHelp {
+			return ErrCompletionRequested
+		}
+		os.Exit(0)
+	}
+
+	// Now that the keys can be looked up, replace the prototype elements of
+	// the slices of structs by their actual elements.
+	if p.Exists != nil {
+		err = expandSlices(root, "", p.Exists)
+		if err != nil {
+			return fmt.Errorf("expanding slices: %w", err)
+		}
+
+		fields, err = resolve(root)
+		if err != nil {
+			return fmt.Errorf("resolving struct: %w", err)
+		}
+
+		err = checkCollisions(fields)
+		if err != nil {
+			return fmt.Errorf("checking names: %w", err)
+		}
+	}
+
 	for _, hook := range p.hooks {
+		var errs Errors
 		for _, field := range fields {
 			err := hook(ctx, field)
-			if err != nil {
-				return fmt.Errorf("executing hook on field %s: %w", field.Path, err)
+			if err == nil {
+				continue
 			}
+
+			err = fmt.Errorf("executing hook on field %s: %w", field.Path, err)
+			if !p.CollectErrors {
+				return err
+			}
+			errs = append(errs, err)
+		}
+
+		if len(errs) != 0 {
+			return errs
 		}
 	}
 
 	return nil
 }
 
+// output returns the writer the usage message and completion scripts are
+// written to.
+func (p *Processor) output() io.Writer {
+	if p.Output != nil {
+		return p.Output
+	}
+	return os.Stdout
+}
+
 func (p *Processor) AddHooks(hooks ...Hook) {
 	p.hooks = append(p.hooks, hooks...)
 }
@@ -105,6 +244,9 @@ func walk(v reflect.Value, s reflect.StructField, p *Field) (field *Field, err e
 		field.Anonymous = true
 	} else {
 		field.Path = fmt.Sprintf("%s.%s", p.Path, s.Name)
+		if p.Value.Kind() == reflect.Slice {
+			field.Path = fmt.Sprintf("%s[%s]", p.Path, s.Name)
+		}
 		field.Anonymous = s.Anonymous
 		field.Tags = s.Tag
 
@@ -123,6 +265,7 @@ func walk(v reflect.Value, s reflect.StructField, p *Field) (field *Field, err e
 				return nil, fmt.Errorf("cannot address %s for path %s", v.Type(), field.Path)
 			}
 			v.Set(reflect.New(v.Type().Elem()))
+			field.allocated = true
 		}
 		v = v.Elem()
 	}
@@ -131,6 +274,19 @@ func walk(v reflect.Value, s reflect.StructField, p *Field) (field *Field, err e
 		return field, nil
 	}
 
+	// The elements of a slice of structs are only known once the keys can be
+	// looked up: walk a prototype element for the usage message, replaced by
+	// the actual elements in expandSlices.
+	if field.isStructSlice() {
+		element, err := walkElement(field, newElement(v.Type().Elem()), prototypeIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		field.Children = append(field.Children, element)
+		return field, nil
+	}
+
 outer:
 	for i := 0; i < v.Type().NumField(); i++ {
 		structField := v.Type().Field(i)
@@ -138,14 +294,20 @@ outer:
 			continue
 		}
 
-		// Look for the field's own type in it's ancestry. If we find one,
-		// consider this field as a leaf because it would otherwise end-up in
-		// an infinite loop. See gorm.io/gorm.DB (in v1.22.4) for an example.
-		// Fixes #46.
+		// Look for the field's own type (or the type of its elements for a
+		// slice) in it's ancestry. If we find one, consider this field as a
+		// leaf because it would otherwise end-up in an infinite loop. See
+		// gorm.io/gorm.DB (in v1.22.4) for an example. Fixes #46.
 		fieldType := structField.Type
 		for fieldType.Kind() == reflect.Ptr {
 			fieldType = fieldType.Elem()
 		}
+		if fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+		}
 		for ancestor := field; ancestor != nil; ancestor = ancestor.Parent {
 			ancestorType := ancestor.Value.Type()
 			for ancestorType.Kind() == reflect.Ptr {
@@ -289,7 +451,7 @@ func newCycleError(deps dependencies) error {
 		for _, path := range paths {
 			for fieldPath := range deps[path[len(path)-1]] {
 				if fieldPath == path[0] {
-					return fmt.Errorf("cycle detected: %s", strings.Join(path, " -> "))
+					return &CycleError{Cycle: append([]string(nil), path...)}
 				}
 
 				next = append(next, append(path, fieldPath))
@@ -317,7 +479,8 @@ func mark(f *Field, key string) bool {
 		}
 	}
 
-	// Derive the key if needed.
+	// Derive the key if needed, keeping the parent's one for the aliases.
+	var parent = key
 	if f.Key != "" {
 		key = key + "." + f.Key
 	}
@@ -342,74 +505,93 @@ func mark(f *Field, key string) bool {
 	if children == 0 {
 		f.Configurable = true
 		f.ConfigurationKey = key[1:]
+		f.AliasKeys = aliasKeys(f, TagAlias, parent)
+		f.DeprecatedKeys = aliasKeys(f, TagDeprecated, parent)
 	}
 
 	// A field with a key should always return true.
 	return true
 }
 
-// DefaultUsageVal prints a usage message that lists the fields with their keys
-// in CLI form (e.g. --foo) and environment variable form (e.g. FOO), as well as
-// the fields descriptions and default values (if any).
-//
-// If called with the "cli" value, only the CLI form is printed, and if called
-// with the "env" value, only the environment variable form is printed. Any
-// other value (including an empty value) prints both forms.
-func DefaultUsageVal(val string, fields []*Field) {
-	var keys []string
-	var options = make(map[string]*Field)
+// checkCollisions makes sure that no two configurable fields share the same
+// environment variable or flag once their keys are formatted, e.g. `cache.ttl`
+// and `cache-ttl` which are both `CACHE_TTL`.
+func checkCollisions(fields []*Field) error {
+	var configurable []*Field
 	for _, f := range fields {
-		if !f.Configurable {
-			continue
+		if f.Configurable {
+			configurable = append(configurable, f)
 		}
-		keys = append(keys, f.ConfigurationKey)
-		options[f.ConfigurationKey] = f
 	}
-	sort.Strings(keys)
 
-	required := flexwriter.New()
-	optional := flexwriter.New()
+	// Sort the fields so the reported collisions are stable.
+	sort.Slice(configurable, func(a, b int) bool {
+		return configurable[a].Path < configurable[b].Path
+	})
 
-	columns := []flexwriter.Column{
-		flexwriter.Rigid{},      // CLI option name
-		flexwriter.Rigid{},      // env variable name
-		flexwriter.Shrinkable{}, // description
-		flexwriter.Rigid{},      // default value
-	}
-	switch val {
-	case "env":
-		columns[0] = flexwriter.Omit{}
-	case "cli":
-		columns[1] = flexwriter.Omit{}
+	var (
+		envs  = make(map[string]*Field)
+		flags = make(map[string]*Field)
+	)
+	for _, f := range configurable {
+		env := Env.FormatFieldKey(f)
+		if other, ok := envs[env]; ok {
+			return fmt.Errorf("fields %s (key %s) and %s (key %s) share the environment variable %s", other.Path, other.ConfigurationKey, f.Path, f.ConfigurationKey, env)
+		}
+		envs[env] = f
+
+		flag := Args.FormatFieldKey(f)
+		if other, ok := flags[flag]; ok {
+			return fmt.Errorf("fields %s (key %s) and %s (key %s) share the flag --%s", other.Path, other.ConfigurationKey, f.Path, f.ConfigurationKey, flag)
+		}
+		flags[flag] = f
 	}
-	required.SetColumns(columns...)
-	optional.SetColumns(columns...)
 
-	for _, key := range keys {
-		field := options[key]
-		desc, _ := field.Tags.Lookup(TagDescription)
+	return nil
+}
 
-		row := []any{"--" + key, Env.FormatKey(key), desc}
+// checkReserved makes sure that no configurable field uses one of the given
+// flags or environment variables, read by zconfig itself.
+func checkReserved(fields []*Field, flags []string, envs []string) error {
+	for _, f := range fields {
+		if !f.Configurable {
+			continue
+		}
 
-		def, ok := field.Tags.Lookup(TagDefault)
-		if ok {
-			optional.WriteRow(append(row, "("+def+")")...)
-		} else {
-			required.WriteRow(row...)
+		flag := Args.FormatFieldKey(f)
+		for _, reserved := range flags {
+			if flag == reserved {
+				return fmt.Errorf("field %s (key %s) uses the reserved flag --%s", f.Path, f.ConfigurationKey, flag)
+			}
 		}
-	}
 
-	fmt.Printf("\nRequired parameters:\n")
-	_ = required.Flush()
+		env := Env.FormatFieldKey(f)
+		for _, reserved := range envs {
+			if env == reserved {
+				return fmt.Errorf("field %s (key %s) uses the reserved environment variable %s", f.Path, f.ConfigurationKey, env)
+			}
+		}
+	}
 
-	fmt.Printf("\nOptional parameters:\n")
-	_ = optional.Flush()
+	return nil
 }
 
-// DefaultUsage prints a usage message as DefaultUsageVal would with an empty
-// value.
-//
-// Deprecated: use DefaultUsageVal.
-func DefaultUsage(fields []*Field) {
-	DefaultUsageVal("", fields)
+// aliasKeys computes the configuration keys of the comma-separated aliases
+// given by a tag of the field. Like the field's key, the aliases are relative
+// to the key of the parent.
+func aliasKeys(f *Field, tag string, parent string) (keys []string) {
+	aliases, ok := f.Tags.Lookup(tag)
+	if !ok {
+		return nil
+	}
+
+	for _, alias := range strings.Split(aliases, ",") {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			continue
+		}
+		keys = append(keys, (parent + "." + alias)[1:])
+	}
+
+	return keys
 }
@@ -186,6 +186,18 @@ func TestNewCycleError(t *testing.T) {
 	}
 
 	t.Log(err)
+
+	var cerr *CycleError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("expected a cycle error, got %T", err)
+	}
+
+	if len(cerr.Cycle) < 2 || deps[cerr.Cycle[len(cerr.Cycle)-1]] == nil {
+		t.Fatalf("unexpected cycle: %v", cerr.Cycle)
+	}
+	if _, ok := deps[cerr.Cycle[len(cerr.Cycle)-1]][cerr.Cycle[0]]; !ok {
+		t.Fatalf("cycle %v doesn't loop back to its start", cerr.Cycle)
+	}
 }
 
 func TestDependencies(t *testing.T) {
@@ -271,6 +283,23 @@ func TestProcessorHooks(t *testing.T) {
 		}
 	})
 
+	t.Run("setup error", func(t *testing.T) {
+		testHook := func(ctx context.Context, field *Field) error {
+			t.Fatalf("unexpected hook execution on field %s", field.Path)
+			return nil
+		}
+
+		p := NewProcessor(testHook)
+		p.Setup = func(fields []*Field) error {
+			return errors.New("loading configuration file: an error")
+		}
+
+		err := p.Process(context.Background(), new(Service))
+		if err == nil || err.Error() != "setting up: loading configuration file: an error" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
 	t.Run("error", func(t *testing.T) {
 		testHook := func(ctx context.Context, field *Field) error {
 			return errors.New("an error")
@@ -285,3 +314,95 @@ func TestProcessorHooks(t *testing.T) {
 		}
 	})
 }
+
+func TestProcessorCollisions(t *testing.T) {
+	for name, c := range map[string]struct {
+		s        interface{}
+		expected string
+	}{
+		"env": {
+			s: new(struct {
+				Cache struct {
+					TTL int `key:"ttl"`
+				} `key:"cache"`
+				CacheTTL int `key:"cache-ttl"`
+			}),
+			expected: "fields $.Cache.TTL (key cache.ttl) and $.CacheTTL (key cache-ttl) share the environment variable CACHE_TTL",
+		},
+		"env tag": {
+			s: new(struct {
+				Port    int `key:"port"`
+				APIPort int `key:"api-port" env:"PORT"`
+			}),
+			expected: "fields $.APIPort (key api-port) and $.Port (key port) share the environment variable PORT",
+		},
+		"flag tag": {
+			s: new(struct {
+				Port    int `key:"port"`
+				APIPort int `key:"api-port" env:"API_PORT" flag:"port"`
+			}),
+			expected: "fields $.APIPort (key api-port) and $.Port (key port) share the flag --port",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			hook := func(ctx context.Context, field *Field) error {
+				t.Fatalf("unexpected hook execution on field %s", field.Path)
+				return nil
+			}
+
+			err := NewProcessor(hook).Process(context.Background(), c.s)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestProcessorCollectErrors(t *testing.T) {
+	var s struct {
+		A string `key:"a"`
+		B string `key:"b"`
+		C string `key:"c"`
+	}
+
+	var repository Repository
+	repository.AddProviders(TestProvider{"test", map[string]string{"b": "b"}})
+	repository.AddParsers(ParseString)
+
+	var initialized = false
+	next := func(ctx context.Context, field *Field) error {
+		initialized = true
+		return nil
+	}
+
+	p := NewProcessor(repository.Hook, next)
+	p.CollectErrors = true
+
+	err := p.Process(context.Background(), &s)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected a list of errors, got %T", err)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), err)
+	}
+
+	for _, key := range []string{"missing key a", "missing key c"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("expected the error to report %s, got: %v", key, err)
+		}
+	}
+
+	if initialized {
+		t.Error("expected the next hooks not to be executed")
+	}
+}
@@ -1,7 +1,9 @@
 package zconfig
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -52,6 +54,22 @@ func (p *ArgsProvider) Retrieve(key string) (value interface{}, found bool, err
 	return value, found, nil
 }
 
+// RetrieveField will return the value from the parsed command-line arguments
+// for the flag of the field, as given by FormatFieldKey.
+func (p *ArgsProvider) RetrieveField(f *Field) (value interface{}, source string, found bool, err error) {
+	value, found = p.Args[p.FormatFieldKey(f)]
+	return value, "", found, nil
+}
+
+// FormatFieldKey returns the name of the flag of a field (without the leading
+// dashes): the one given by its `flag` tag, or else its configuration key.
+func (p *ArgsProvider) FormatFieldKey(f *Field) string {
+	if flag, ok := f.Tags.Lookup(TagFlag); ok && flag != "" {
+		return flag
+	}
+	return f.ConfigurationKey
+}
+
 // Name of the provider.
 func (ArgsProvider) Name() string {
 	return "args"
@@ -62,8 +80,26 @@ func (ArgsProvider) Priority() int {
 	return 1
 }
 
+const (
+	// FileSuffix is the suffix of the environment variables referencing a
+	// file holding the value of the variable without the suffix.
+	FileSuffix = "_FILE"
+
+	// ProviderEnvFile is the source of the values read from a file
+	// referenced by an environment variable.
+	ProviderEnvFile = "env-file"
+
+	// ProviderDotenvFile is the source of the values read from a file
+	// referenced by a dotenv variable.
+	ProviderDotenvFile = "dotenv-file"
+)
+
 // A Provider that implements the repository.Provider interface.
-type EnvProvider struct{}
+type EnvProvider struct {
+	// Prefix of the environment variables, e.g. `MYAPP` to lookup the
+	// `server.addr` key in the `MYAPP_SERVER_ADDR` variable.
+	Prefix string
+}
 
 // NewEnvProvider returns a provider that will lookup keys in the environment
 // variables.
@@ -71,11 +107,54 @@ func NewEnvProvider() (p EnvProvider) {
 	return p
 }
 
+// NewEnvProviderWithPrefix returns a provider that will lookup keys in the
+// environment variables starting with the given prefix, which avoids
+// collisions between several programs sharing the same environment.
+func NewEnvProviderWithPrefix(prefix string) (p EnvProvider) {
+	p.Prefix = prefix
+	return p
+}
+
 // Retrieve will return the value from the parsed environment variables.
 // Variables are parsed the first time the method is called.
 func (p EnvProvider) Retrieve(key string) (value interface{}, found bool, err error) {
-	value, found = os.LookupEnv(p.FormatKey(key))
-	return value, found, nil
+	value, _, found, err = p.RetrieveSource(key)
+	return value, found, err
+}
+
+// RetrieveSource will return the value from the parsed environment variables.
+// If the variable isn't set but the same variable suffixed by `_FILE` is
+// (e.g. `DATABASE_PASSWORD_FILE` for `DATABASE_PASSWORD`), the value is read
+// from the file it references and the source is ProviderEnvFile.
+func (p EnvProvider) RetrieveSource(key string) (value interface{}, source string, found bool, err error) {
+	return p.lookup(p.FormatKey(key))
+}
+
+// RetrieveField behaves like RetrieveSource for the environment variable of
+// the field, as given by FormatFieldKey.
+func (p EnvProvider) RetrieveField(f *Field) (value interface{}, source string, found bool, err error) {
+	return p.lookup(p.FormatFieldKey(f))
+}
+
+// lookup returns the value of an environment variable, or the content of the
+// file referenced by the same variable suffixed by `_FILE`.
+func (p EnvProvider) lookup(env string) (value interface{}, source string, found bool, err error) {
+	value, found = os.LookupEnv(env)
+	if found {
+		return value, "", true, nil
+	}
+
+	path, found := os.LookupEnv(env + FileSuffix)
+	if !found {
+		return value, "", false, nil
+	}
+
+	value, err = readValueFile(path)
+	if err != nil {
+		return nil, ProviderEnvFile, false, fmt.Errorf("reading file referenced by %s: %w", env+FileSuffix, err)
+	}
+
+	return value, ProviderEnvFile, true, nil
 }
 
 // Name of the provider.
@@ -96,6 +175,62 @@ func FormatEnvKey(key string) string {
 	return strings.ReplaceAll(env, "-", "_")
 }
 
-func (EnvProvider) FormatKey(key string) (env string) {
-	return FormatEnvKey(key)
+// FormatKey transforms configuration keys to environment variable format,
+// prefixed by the provider's prefix if any.
+// Examples with the `myapp` prefix: "database.url" -> "MYAPP_DATABASE_URL"
+func (p EnvProvider) FormatKey(key string) (env string) {
+	if p.Prefix == "" {
+		return FormatEnvKey(key)
+	}
+	return strings.TrimSuffix(FormatEnvKey(p.Prefix), "_") + "_" + FormatEnvKey(key)
+}
+
+// FormatFieldKey returns the environment variable of a field: the one given by
+// its `env` tag as is (i.e. without prefix), or else the one derived from its
+// configuration key by FormatKey.
+func (p EnvProvider) FormatFieldKey(f *Field) string {
+	if env, ok := f.Tags.Lookup(TagEnv); ok && env != "" {
+		return env
+	}
+	return p.FormatKey(f.ConfigurationKey)
+}
+
+// readValueFile returns the content of a file holding a single value, without
+// its trailing newlines.
+func readValueFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// lookupKey walks a tree of nested mappings, as decoded from a structured
+// document, along the dot-separated parts of the key. Elements of lists are
+// addressed by their index. A null value is not found, so the default value
+// of the field applies.
+func lookupKey(tree interface{}, key string) (value interface{}, found bool) {
+	value = tree
+	for _, part := range strings.Split(key, ".") {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			value, found = node[part]
+		case map[interface{}]interface{}:
+			value, found = node[part]
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			value, found = node[i], true
+		default:
+			return nil, false
+		}
+
+		if !found || value == nil {
+			return nil, false
+		}
+	}
+
+	return value, true
 }
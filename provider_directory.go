@@ -0,0 +1,120 @@
+package zconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A Provider that implements the repository.Provider interface for
+// directories holding one file per key, like mounted Kubernetes ConfigMaps and
+// Secrets or Docker secrets.
+type DirectoryProvider struct {
+	// Path of the directory.
+	Path string
+
+	// TrimNewline removes the trailing newlines from the content of the
+	// files, which most editors and tools add. Enabled by default.
+	TrimNewline bool
+}
+
+// NewDirectoryProvider creates a provider that looks up keys in the files of
+// the given directory. Files are read upon retrieval, so updates of the
+// directory are taken into account.
+func NewDirectoryProvider(path string) (*DirectoryProvider, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("opening directory: %s is not a directory", path)
+	}
+
+	// Make path absolute to avoid issues with working directory changes
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return &DirectoryProvider{
+		Path:        path,
+		TrimNewline: true,
+	}, nil
+}
+
+// Retrieve will return the content of the first file matching the key,
+// looking for the file at the path made of the parts of the key (e.g.
+// `database/password` for `database.password`), then for the file named after
+// the key itself (`database.password`) and finally for the file named after
+// its environment variable form (`DATABASE_PASSWORD`).
+//
+// When the directory follows the layout used by Kubernetes for atomic updates,
+// i.e. has a `..data` symlink to the current version of the files, the files
+// are read from the target of this symlink so they all come from the same
+// version.
+func (p *DirectoryProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	root := p.Path
+	if data, err := filepath.EvalSymlinks(filepath.Join(root, "..data")); err == nil {
+		root = data
+	}
+
+	var names []string
+	for _, parts := range [][]string{strings.Split(key, "."), {key}, {FormatEnvKey(key)}} {
+		if validPathParts(parts) {
+			names = append(names, filepath.Join(parts...))
+		}
+	}
+
+	for _, name := range names {
+		path := filepath.Join(root, name)
+
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("reading file for key %s: %w", key, err)
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("reading file for key %s: %w", key, err)
+		}
+
+		value := string(content)
+		if p.TrimNewline {
+			value = strings.TrimRight(value, "\r\n")
+		}
+
+		return value, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// validPathParts checks that the parts of a key can be used as a path that
+// stays inside the directory.
+func validPathParts(parts []string) bool {
+	for _, part := range parts {
+		if part == "" || part == "." || part == ".." || strings.ContainsAny(part, `/\`) {
+			return false
+		}
+	}
+	return true
+}
+
+// Name of the provider.
+func (p *DirectoryProvider) Name() string {
+	return "directory"
+}
+
+// Priority of the provider, PriorityFile.
+func (p *DirectoryProvider) Priority() int {
+	return PriorityFile
+}
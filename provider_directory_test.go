@@ -0,0 +1,146 @@
+package zconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirectoryProvider(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"database/password": "nested-secret\n",
+		"api.key":           "dotted-secret\n",
+		"REDIS_PASSWORD":    "env-secret\r\n",
+		"raw":               "  spaces  ",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// Directories are not values.
+	if err := os.MkdirAll(filepath.Join(tmpDir, "server", "tls"), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+
+	provider, err := NewDirectoryProvider(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+		found    bool
+	}{
+		{"database.password", "nested-secret", true},
+		{"api.key", "dotted-secret", true},
+		{"redis.password", "env-secret", true},
+		{"raw", "  spaces  ", true},
+		{"server.tls", "", false},
+		{"database", "", false},
+		{"..", "", false},
+		{"nonexistent", "", false},
+	}
+
+	for _, test := range tests {
+		value, found, err := provider.Retrieve(test.key)
+		if err != nil {
+			t.Errorf("Unexpected error for key %s: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found {
+			t.Errorf("For key %s: expected found=%v, got found=%v", test.key, test.found, found)
+			continue
+		}
+
+		if found && value != test.expected {
+			t.Errorf("For key %s: expected value=%q, got value=%q", test.key, test.expected, value)
+		}
+	}
+
+	provider.TrimNewline = false
+	value, _, _ := provider.Retrieve("database.password")
+	if value != "nested-secret\n" {
+		t.Errorf("Expected untrimmed value, got %q", value)
+	}
+}
+
+func TestDirectoryProviderKubernetesLayout(t *testing.T) {
+	// Reproduce the layout of a mounted ConfigMap:
+	//   ..2024_01_01_00_00_00.000000000/password
+	//   ..data -> ..2024_01_01_00_00_00.000000000
+	//   password -> ..data/password
+	tmpDir := t.TempDir()
+
+	version := func(name, content string) {
+		dir := filepath.Join(tmpDir, name)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "password"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	version("..v1", "first\n")
+	if err := os.Symlink("..v1", filepath.Join(tmpDir, "..data")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "password"), filepath.Join(tmpDir, "password")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	provider, err := NewDirectoryProvider(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value, found, err := provider.Retrieve("password")
+	if err != nil || !found || value != "first" {
+		t.Fatalf("Expected 'first', got %q (found=%v, err=%v)", value, found, err)
+	}
+
+	// Atomically swap the ..data symlink like the kubelet does.
+	version("..v2", "second\n")
+	if err := os.Symlink("..v2", filepath.Join(tmpDir, "..data_tmp")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := os.Rename(filepath.Join(tmpDir, "..data_tmp"), filepath.Join(tmpDir, "..data")); err != nil {
+		t.Fatalf("Failed to swap symlink: %v", err)
+	}
+
+	value, found, err = provider.Retrieve("password")
+	if err != nil || !found || value != "second" {
+		t.Fatalf("Expected 'second', got %q (found=%v, err=%v)", value, found, err)
+	}
+}
+
+func TestNewDirectoryProvider(t *testing.T) {
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := NewDirectoryProvider("/nonexistent/path")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		_, err := NewDirectoryProvider(path)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package zconfig
 
 import (
 	"bufio"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +11,7 @@ import (
 // A Provider that implements the repository.Provider interface for dotenv files.
 type DotenvProvider struct {
 	vars map[string]string
+	dir  string
 }
 
 // NewDotenvProvider creates a provider that loads environment variables from a .env file
@@ -32,6 +34,7 @@ func NewDotenvProviderWithPath(path string) *DotenvProvider {
 		}
 	}
 
+	p.dir = filepath.Dir(path)
 	p.loadFile(path)
 	return p
 }
@@ -104,10 +107,52 @@ func (p *DotenvProvider) unquoteValue(value string) string {
 
 // Retrieve will return the value from the loaded dotenv variables.
 func (p *DotenvProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	value, _, found, err = p.RetrieveSource(key)
+	return value, found, err
+}
+
+// RetrieveSource will return the value from the loaded dotenv variables. Like
+// for the EnvProvider, if the variable isn't set but the same variable
+// suffixed by `_FILE` is, the value is read from the file it references
+// (relative paths being relative to the dotenv file) and the source is
+// ProviderDotenvFile.
+func (p *DotenvProvider) RetrieveSource(key string) (value interface{}, source string, found bool, err error) {
 	// Use the same key formatting as EnvProvider for consistency
-	envKey := FormatEnvKey(key)
+	return p.lookup(FormatEnvKey(key))
+}
+
+// RetrieveField behaves like RetrieveSource for the variable given by the `env`
+// tag of the field, if any.
+func (p *DotenvProvider) RetrieveField(f *Field) (value interface{}, source string, found bool, err error) {
+	if env, ok := f.Tags.Lookup(TagEnv); ok && env != "" {
+		return p.lookup(env)
+	}
+	return p.RetrieveSource(f.ConfigurationKey)
+}
+
+// lookup returns the value of a variable, or the content of the file
+// referenced by the same variable suffixed by `_FILE`.
+func (p *DotenvProvider) lookup(envKey string) (value interface{}, source string, found bool, err error) {
 	value, found = p.vars[envKey]
-	return value, found, nil
+	if found {
+		return value, "", true, nil
+	}
+
+	path, found := p.vars[envKey+FileSuffix]
+	if !found {
+		return value, "", false, nil
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, path)
+	}
+
+	value, err = readValueFile(path)
+	if err != nil {
+		return nil, ProviderDotenvFile, false, fmt.Errorf("reading file referenced by %s: %w", envKey+FileSuffix, err)
+	}
+
+	return value, ProviderDotenvFile, true, nil
 }
 
 // Name of the provider.
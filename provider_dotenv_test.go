@@ -226,3 +226,41 @@ CARRIAGE_RETURN="line1\rline2"`
 		}
 	}
 }
+
+func TestDotenvProviderFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "secrets"), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "secrets", "db"), []byte("db-secret\n"), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	content := `DATABASE_PASSWORD_FILE=secrets/db
+API_KEY=plain
+API_KEY_FILE=secrets/db
+BROKEN_FILE=secrets/missing
+`
+	if err := os.WriteFile(envFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	provider := NewDotenvProviderWithPath(envFile)
+
+	value, source, found, err := provider.RetrieveSource("database.password")
+	if err != nil || !found || value != "db-secret" || source != ProviderDotenvFile {
+		t.Errorf("Expected 'db-secret' from %s, got %q from %q (found=%v, err=%v)", ProviderDotenvFile, value, source, found, err)
+	}
+
+	value, source, found, err = provider.RetrieveSource("api.key")
+	if err != nil || !found || value != "plain" || source != "" {
+		t.Errorf("Expected 'plain', got %q from %q (found=%v, err=%v)", value, source, found, err)
+	}
+
+	_, _, _, err = provider.RetrieveSource("broken")
+	if err == nil {
+		t.Error("Expected an error for an unreadable file")
+	}
+}
@@ -0,0 +1,182 @@
+package zconfig
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PriorityFile is the priority of the providers of configuration files and
+// directories, which come after args (1), env (2) and dotenv (3). Contrary to
+// the DotenvProvider, their constructors report a missing or malformed file
+// as an error.
+const PriorityFile = 4
+
+// ConfigFileExtensions lists the extensions of the configuration files looked
+// up by FindConfigFile, in order of preference.
+var ConfigFileExtensions = []string{".yaml", ".yml", ".json", ".toml", ".hcl", ".ini", ".properties"}
+
+// NewFileProvider creates the provider matching the format of the file at the
+// given path, as detected from its extension: `.yaml` or `.yml`, `.json`,
+// `.toml`, `.hcl`, `.ini`, `.properties` or `.env`.
+func NewFileProvider(path string) (Provider, error) {
+	var (
+		p   Provider
+		err error
+	)
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		p, err = providerOrError(NewYAMLProvider(path))
+	case ".json":
+		p, err = providerOrError(NewJSONProvider(path))
+	case ".toml":
+		p, err = providerOrError(NewTOMLProvider(path))
+	case ".hcl":
+		p, err = providerOrError(NewHCLProvider(path))
+	case ".ini":
+		p, err = providerOrError(NewINIProvider(path))
+	case ".properties":
+		p, err = providerOrError(NewPropertiesProvider(path))
+	case ".env":
+		// The DotenvProvider ignores missing files, which isn't suitable
+		// for a file that was explicitly asked for.
+		_, err = os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening dotenv file: %w", err)
+		}
+		p = NewDotenvProviderWithPath(path)
+	default:
+		return nil, fmt.Errorf("unsupported configuration file format %q for %s", ext, path)
+	}
+
+	return p, err
+}
+
+// providerOrError avoids returning a typed nil pointer as a Provider.
+func providerOrError[P Provider](p P, err error) (Provider, error) {
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// loadFile opens the file at the given path and loads it with the constructor
+// of a provider reading from it, wrapping the errors with the format of the
+// file.
+func loadFile[P Provider](path, format string, load func(r io.Reader) (P, error)) (p P, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return p, fmt.Errorf("opening %s file: %w", format, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	p, err = load(file)
+	if err != nil {
+		return p, fmt.Errorf("loading %s file %s: %w", format, path, err)
+	}
+
+	return p, nil
+}
+
+// FindConfigFile looks for a `config` file with one of the
+// ConfigFileExtensions in the well-known locations for the given application,
+// in order: the working directory, `$XDG_CONFIG_HOME/<app>` (which defaults to
+// `$HOME/.config/<app>`) and `/etc/<app>`. The first file found is returned.
+func FindConfigFile(app string) (path string, found bool) {
+	dirs := []string{"."}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		dirs = append(dirs, filepath.Join(configHome, app))
+	}
+
+	dirs = append(dirs, filepath.Join("/etc", app))
+
+	for _, dir := range dirs {
+		for _, ext := range ConfigFileExtensions {
+			path = filepath.Join(dir, "config"+ext)
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				return path, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// configFileProviders returns the providers of the configuration files given
+// as a comma-separated list by the `--config` flag or the `CONFIG_FILE`
+// environment variable, or else of the file found by FindConfigFile for the
+// given application, unless its name is empty. Later files override earlier
+// ones.
+func configFileProviders(app string) (providers []Provider, err error) {
+	var paths []string
+
+	raw, found, _ := Args.Retrieve("config")
+	if !found {
+		raw, found, _ = Env.Retrieve("config.file")
+	}
+
+	if found {
+		for _, path := range strings.Split(raw.(string), ",") {
+			path = strings.TrimSpace(path)
+			if path != "" {
+				paths = append(paths, path)
+			}
+		}
+	} else if app != "" {
+		if path, ok := FindConfigFile(app); ok {
+			paths = append(paths, path)
+		}
+	}
+
+	// Providers with the same priority are consulted in the order they are
+	// added, so add the files overriding the others first.
+	for i := len(paths) - 1; i >= 0; i-- {
+		p, err := NewFileProvider(paths[i])
+		if err != nil {
+			return nil, fmt.Errorf("loading configuration file: %w", err)
+		}
+		providers = append(providers, p)
+	}
+
+	return providers, nil
+}
+
+// configFileFlags are the flags read to load the configuration files into the
+// default repository, which the fields cannot use, as well as the
+// `CONFIG_FILE` environment variable.
+var configFileFlags = []string{"config", "dotenv"}
+
+// loadConfigFiles makes sure the fields don't use the flags and environment
+// variable reserved for the configuration files, and adds the providers of the
+// files to the default repository, once.
+func loadConfigFiles(fields []*Field) error {
+	err := checkReserved(fields, configFileFlags, []string{Env.FormatKey("config.file")})
+	if err != nil {
+		return err
+	}
+
+	if configFilesLoaded {
+		return nil
+	}
+
+	providers, err := configFileProviders(appName)
+	if err != nil {
+		return err
+	}
+
+	DefaultRepository.AddProviders(providers...)
+	configFilesLoaded = true
+	return nil
+}
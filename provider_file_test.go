@@ -0,0 +1,265 @@
+package zconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewFileProvider(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"config.yaml":       "foo:\n  bar: baz\n",
+		"config.YML":        "foo:\n  bar: baz\n",
+		"config.json":       `{"foo": {"bar": "baz"}}`,
+		"config.toml":       "[foo]\nbar = \"baz\"\n",
+		"config.hcl":        "foo {\n  bar = \"baz\"\n}\n",
+		"config.ini":        "[foo]\nbar = baz\n",
+		"config.properties": "foo.bar=baz\n",
+		"custom.env":        "FOO_BAR=baz\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, name)
+			err := os.WriteFile(path, []byte(content), 0644)
+			if err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			provider, err := NewFileProvider(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			value, found, err := provider.Retrieve("foo.bar")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !found || value != "baz" {
+				t.Errorf("Expected 'baz', got %#v (found=%v)", value, found)
+			}
+		})
+	}
+
+	for name, path := range map[string]string{
+		"unsupported format": filepath.Join(tmpDir, "config.xml"),
+		"missing file":       filepath.Join(tmpDir, "missing.yaml"),
+		"missing dotenv":     filepath.Join(tmpDir, "missing.env"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			provider, err := NewFileProvider(path)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if provider != nil {
+				t.Fatalf("expected a nil provider, got %#v", provider)
+			}
+		})
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	workDir := filepath.Join(tmpDir, "work")
+	configHome := filepath.Join(tmpDir, "config")
+
+	for _, dir := range []string{workDir, filepath.Join(configHome, "app")} {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+	}
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(oldDir); err != nil {
+			t.Errorf("Failed to restore directory: %v", err)
+		}
+	}()
+
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatalf("Failed to change to temp directory: %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	_, found := FindConfigFile("app")
+	if found {
+		t.Fatal("Expected not to find a configuration file")
+	}
+
+	xdgPath := filepath.Join(configHome, "app", "config.toml")
+	err = os.WriteFile(xdgPath, nil, 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	path, found := FindConfigFile("app")
+	if !found || path != xdgPath {
+		t.Fatalf("Expected %s, got %s (found=%v)", xdgPath, path, found)
+	}
+
+	err = os.WriteFile(filepath.Join(workDir, "config.json"), nil, 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	path, found = FindConfigFile("app")
+	if !found || path != "config.json" {
+		t.Fatalf("Expected config.json, got %s (found=%v)", path, found)
+	}
+}
+
+func TestConfigFileProviders(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "first.yaml")
+	second := filepath.Join(tmpDir, "second.json")
+
+	err := os.WriteFile(first, []byte("foo: first\nbar: first\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	err = os.WriteFile(second, []byte(`{"foo": "second"}`), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	oldArgs := Args
+	defer func() {
+		Args = oldArgs
+	}()
+
+	t.Run("flag", func(t *testing.T) {
+		Args = &ArgsProvider{Args: map[string]string{"config": first + ", " + second}}
+
+		providers, err := configFileProviders("app")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var repository Repository
+		repository.AddProviders(providers...)
+
+		for key, expected := range map[string]string{"foo": "second", "bar": "first"} {
+			value, _, found, err := repository.Retrieve(key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !found || value != expected {
+				t.Errorf("For key %s: expected %s, got %#v (found=%v)", key, expected, value, found)
+			}
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		Args = &ArgsProvider{Args: map[string]string{}}
+		t.Setenv("CONFIG_FILE", second)
+
+		providers, err := configFileProviders("app")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var repository Repository
+		repository.AddProviders(providers...)
+
+		value, provider, found, err := repository.Retrieve("foo")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !found || value != "second" || provider != "json" {
+			t.Errorf("Expected 'second' from json, got %#v from %s (found=%v)", value, provider, found)
+		}
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		Args = &ArgsProvider{Args: map[string]string{}}
+
+		oldDir, err := os.Getwd()
+		if err != nil {
+			t.Fatalf("Failed to get current directory: %v", err)
+		}
+		defer func() {
+			if err := os.Chdir(oldDir); err != nil {
+				t.Errorf("Failed to restore directory: %v", err)
+			}
+		}()
+
+		workDir := t.TempDir()
+		err = os.WriteFile(filepath.Join(workDir, "config.json"), []byte(`{"foo": "found"}`), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		if err := os.Chdir(workDir); err != nil {
+			t.Fatalf("Failed to change to temp directory: %v", err)
+		}
+
+		providers, err := configFileProviders("app")
+		if err != nil || len(providers) != 1 {
+			t.Errorf("expected the configuration file to be found, got %v (%v)", providers, err)
+		}
+
+		providers, err = configFileProviders("")
+		if err != nil || len(providers) != 0 {
+			t.Errorf("expected the lookup to be disabled, got %v (%v)", providers, err)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		Args = &ArgsProvider{Args: map[string]string{"config": filepath.Join(tmpDir, "missing.yaml")}}
+
+		_, err := configFileProviders("app")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
+
+func TestLoadConfigFilesReserved(t *testing.T) {
+	for name, c := range map[string]struct {
+		s        interface{}
+		expected string
+	}{
+		"flag": {
+			s: new(struct {
+				Config string `key:"config"`
+			}),
+			expected: "field $.Config (key config) uses the reserved flag --config",
+		},
+		"env": {
+			s: new(struct {
+				Config struct {
+					File string `key:"file" flag:"config-file"`
+				} `key:"config"`
+			}),
+			expected: "field $.Config.File (key config.file) uses the reserved environment variable CONFIG_FILE",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			hook := func(ctx context.Context, field *Field) error {
+				return nil
+			}
+
+			// Without configuration files, the names are free.
+			err := NewProcessor(hook).Process(context.Background(), c.s)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			p := NewProcessor(hook)
+			p.Setup = loadConfigFiles
+			err = p.Process(context.Background(), c.s)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
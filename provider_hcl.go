@@ -0,0 +1,209 @@
+package zconfig
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// A Provider that implements the repository.Provider interface for HCL
+// documents.
+type HCLProvider struct {
+	tree interface{}
+}
+
+// NewHCLProvider creates a provider that loads the HCL document at the given
+// path.
+func NewHCLProvider(path string) (*HCLProvider, error) {
+	return loadFile(path, "hcl", func(r io.Reader) (*HCLProvider, error) {
+		return newHCLProvider(r, path)
+	})
+}
+
+// NewHCLProviderFromReader creates a provider that loads the HCL document read
+// from r. The attributes are evaluated without variables nor functions, and
+// the errors are returned as hcl.Diagnostics, which include the source range
+// of each problem.
+func NewHCLProviderFromReader(r io.Reader) (*HCLProvider, error) {
+	return newHCLProvider(r, "<input>")
+}
+
+func newHCLProvider(r io.Reader, filename string) (*HCLProvider, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading hcl: %w", err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing hcl: %w", diags)
+	}
+
+	tree, diags := decodeHCLBody(file.Body.(*hclsyntax.Body))
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("evaluating hcl: %w", diags)
+	}
+
+	return &HCLProvider{tree: tree}, nil
+}
+
+// decodeHCLBody evaluates the attributes of a body and decodes its blocks into
+// nested mappings. The labels of a block add a nesting level each, and blocks
+// repeated with the same type and labels form a list. The blocks of a type
+// must all have the same number of labels.
+func decodeHCLBody(body *hclsyntax.Body) (tree map[string]interface{}, diags hcl.Diagnostics) {
+	tree = make(map[string]interface{})
+	firstBlocks := make(map[string]*hclsyntax.Block)
+
+	for name, attr := range body.Attributes {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+
+		v, err := ctyToNative(val)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported value",
+				Detail:   fmt.Sprintf("The value of attribute %q cannot be used as configuration: %s.", name, err),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			continue
+		}
+
+		tree[name] = v
+	}
+
+	for _, block := range body.Blocks {
+		if _, exists := body.Attributes[block.Type]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate name",
+				Detail:   fmt.Sprintf("The name %q is already used by an attribute.", block.Type),
+				Subject:  block.TypeRange.Ptr(),
+			})
+			continue
+		}
+
+		if first, ok := firstBlocks[block.Type]; !ok {
+			firstBlocks[block.Type] = block
+		} else if len(first.Labels) != len(block.Labels) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Inconsistent block labels",
+				Detail:   fmt.Sprintf("The %q blocks must all have the same number of labels, the first one at %s has %d.", block.Type, first.TypeRange, len(first.Labels)),
+				Subject:  block.DefRange().Ptr(),
+			})
+			continue
+		}
+
+		child, childDiags := decodeHCLBody(block.Body)
+		diags = append(diags, childDiags...)
+
+		// Walk down the block type and labels, creating the intermediate
+		// mappings as needed.
+		var (
+			node  = tree
+			parts = append([]string{block.Type}, block.Labels...)
+			last  = parts[len(parts)-1]
+		)
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := node[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				node[part] = next
+			}
+			node = next
+		}
+
+		switch existing := node[last].(type) {
+		case map[string]interface{}:
+			node[last] = []interface{}{existing, child}
+		case []interface{}:
+			node[last] = append(existing, child)
+		default:
+			node[last] = child
+		}
+	}
+
+	return tree, diags
+}
+
+// ctyToNative converts an evaluated HCL value to the native values returned
+// by the structured providers: numbers are returned as int64 when they are
+// integers and float64 otherwise.
+func ctyToNative(val cty.Value) (interface{}, error) {
+	if !val.IsWhollyKnown() {
+		return nil, fmt.Errorf("unknown value")
+	}
+
+	if val.IsNull() {
+		return nil, nil
+	}
+
+	typ := val.Type()
+	switch {
+	case typ == cty.String:
+		return val.AsString(), nil
+	case typ == cty.Bool:
+		return val.True(), nil
+	case typ == cty.Number:
+		f := val.AsBigFloat()
+		if i, accuracy := f.Int64(); accuracy == big.Exact {
+			return i, nil
+		}
+		v, _ := f.Float64()
+		return v, nil
+	case typ.IsListType() || typ.IsSetType() || typ.IsTupleType():
+		list := make([]interface{}, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			v, err := ctyToNative(elem)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case typ.IsMapType() || typ.IsObjectType():
+		m := make(map[string]interface{}, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			v, err := ctyToNative(elem)
+			if err != nil {
+				return nil, err
+			}
+			m[key.AsString()] = v
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ.FriendlyName())
+	}
+}
+
+// Retrieve will return the value found by walking the blocks of the document
+// along the dot-separated parts of the key, e.g. `server.addr` for the `addr`
+// attribute of the `server` block, or `server.web.addr` for the one of the
+// `server "web"` block. Elements of lists (including repeated blocks) are
+// addressed by their index. Values are returned as int64, float64, bool,
+// string, []interface{} or map[string]interface{}.
+func (p *HCLProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	value, found = lookupKey(p.tree, key)
+	return value, found, nil
+}
+
+// Name of the provider.
+func (p *HCLProvider) Name() string {
+	return "hcl"
+}
+
+// Priority of the provider, PriorityFile.
+func (p *HCLProvider) Priority() int {
+	return PriorityFile
+}
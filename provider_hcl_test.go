@@ -0,0 +1,194 @@
+package zconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestHCLProvider(t *testing.T) {
+	content := `
+workers = 2 * 2
+ratio   = 0.5
+hosts   = ["foo", "bar"]
+labels  = { env = "prod" }
+
+server {
+  addr = ":8080"
+
+  tls {
+    enabled = true
+  }
+}
+
+service "web" {
+  port = 80
+}
+
+backend {
+  addr = "10.0.0.1:80"
+}
+
+backend {
+  addr = "10.0.0.2:80"
+}
+`
+
+	provider, err := NewHCLProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected interface{}
+		found    bool
+	}{
+		{"workers", int64(4), true},
+		{"ratio", 0.5, true},
+		{"hosts", []interface{}{"foo", "bar"}, true},
+		{"hosts.0", "foo", true},
+		{"labels.env", "prod", true},
+		{"server.addr", ":8080", true},
+		{"server.tls.enabled", true, true},
+		{"service.web.port", int64(80), true},
+		{"backend.0.addr", "10.0.0.1:80", true},
+		{"backend.1.addr", "10.0.0.2:80", true},
+		{"backend.2.addr", nil, false},
+		{"server.port", nil, false},
+		{"nonexistent", nil, false},
+	}
+
+	for _, test := range tests {
+		value, found, err := provider.Retrieve(test.key)
+		if err != nil {
+			t.Errorf("Unexpected error for key %s: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found {
+			t.Errorf("For key %s: expected found=%v, got found=%v", test.key, test.found, found)
+			continue
+		}
+
+		if found && !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("For key %s: expected value=%#v, got value=%#v", test.key, test.expected, value)
+		}
+	}
+}
+
+func TestNewHCLProvider(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.hcl")
+		err := os.WriteFile(path, []byte("foo {\n  bar = \"baz\"\n}\n"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test hcl file: %v", err)
+		}
+
+		provider, err := NewHCLProvider(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		value, found, _ := provider.Retrieve("foo.bar")
+		if !found || value != "baz" {
+			t.Errorf("Expected 'baz', got %#v (found=%v)", value, found)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewHCLProvider("/nonexistent/path/config.hcl")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	for name, content := range map[string]string{
+		"syntax error":          "foo {\n  bar = \n}\n",
+		"unknown var":           "foo {\n  bar = baz\n}\n",
+		"duplicate name":        "foo = 1\nfoo {\n  bar = 2\n}\n",
+		"mixed labels":          "server { addr = \"a\" }\nserver \"web\" { addr = \"c\" }\n",
+		"mixed labels reversed": "server \"web\" { addr = \"c\" }\nserver { addr = \"a\" }\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.hcl")
+			err := os.WriteFile(path, []byte(content), 0644)
+			if err != nil {
+				t.Fatalf("Failed to create test hcl file: %v", err)
+			}
+
+			_, err = NewHCLProvider(path)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+
+			var diags hcl.Diagnostics
+			if !errors.As(err, &diags) {
+				t.Fatalf("expected diagnostics, got: %v", err)
+			}
+
+			if !strings.Contains(err.Error(), path+":2,") {
+				t.Fatalf("expected the error to include the source range, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestHCLProviderConfigure(t *testing.T) {
+	content := `
+server {
+  addr    = ":8080"
+  workers = 4
+  hosts   = ["foo", "bar"]
+  ports   = [80, 443]
+
+  tls {
+    enabled = true
+  }
+}
+`
+
+	provider, err := NewHCLProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var repository Repository
+	repository.AddProviders(provider)
+	repository.AddParsers(ParseString, ParseNative)
+
+	var s struct {
+		Server struct {
+			Addr    string   `key:"addr"`
+			Workers int      `key:"workers"`
+			Hosts   []string `key:"hosts"`
+			Ports   []int    `key:"ports"`
+			TLS     struct {
+				Enabled bool `key:"enabled"`
+			} `key:"tls"`
+		} `key:"server"`
+	}
+
+	err = NewProcessor(repository.Hook).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Server.Addr != ":8080" || s.Server.Workers != 4 || !s.Server.TLS.Enabled {
+		t.Errorf("unexpected configuration: %+v", s.Server)
+	}
+
+	if !reflect.DeepEqual(s.Server.Hosts, []string{"foo", "bar"}) {
+		t.Errorf("unexpected hosts: %v", s.Server.Hosts)
+	}
+
+	if !reflect.DeepEqual(s.Server.Ports, []int{80, 443}) {
+		t.Errorf("unexpected ports: %v", s.Server.Ports)
+	}
+}
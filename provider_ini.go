@@ -0,0 +1,140 @@
+package zconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A Provider that implements the repository.Provider interface for INI files.
+type INIProvider struct {
+	values map[string]string
+}
+
+// NewINIProvider creates a provider that loads the INI file at the given path.
+func NewINIProvider(path string) (*INIProvider, error) {
+	return loadFile(path, "ini", NewINIProviderFromReader)
+}
+
+// NewINIProviderFromReader creates a provider that loads the INI document read
+// from r. Entries are `key = value` or `key: value` lines, grouped in
+// `[section]` whose name prefixes their keys, and `;` or `#` start comment
+// lines. Values can be double-quoted, in which case escape sequences
+// (including `\uXXXX` Unicode escapes) are replaced, or single-quoted, in
+// which case they are used verbatim. Unquoted values ending with a backslash
+// are continued on the next line.
+func NewINIProviderFromReader(r io.Reader) (*INIProvider, error) {
+	p := &INIProvider{
+		values: make(map[string]string),
+	}
+
+	var (
+		scanner   = bufio.NewScanner(r)
+		section   = ""
+		key       = ""
+		value     strings.Builder
+		number    = 0
+		continued = false
+	)
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+
+		if continued {
+			continued = trailingBackslashes(line)%2 == 1
+			if continued {
+				value.WriteString(line[:len(line)-1])
+				continue
+			}
+			value.WriteString(line)
+			p.values[key] = value.String()
+			continue
+		}
+
+		// Skip empty lines and comments
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", number)
+			}
+
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == "" {
+				return nil, fmt.Errorf("line %d: empty section name", number)
+			}
+			continue
+		}
+
+		// Parse `key = value` or `key: value` format
+		end := strings.IndexAny(line, "=:")
+		if end == -1 {
+			return nil, fmt.Errorf("line %d: expected key = value", number)
+		}
+
+		key = strings.TrimSpace(line[:end])
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", number)
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+
+		raw := strings.TrimSpace(line[end+1:])
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			if len(raw) < 2 || !strings.HasSuffix(raw, `"`) || trailingBackslashes(raw[:len(raw)-1])%2 == 1 {
+				return nil, fmt.Errorf("line %d: unterminated quoted value for key %s", number, key)
+			}
+
+			v, err := unescapeString(raw[1 : len(raw)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: parsing value for key %s: %w", number, key, err)
+			}
+			p.values[key] = v
+		case strings.HasPrefix(raw, `'`):
+			if len(raw) < 2 || !strings.HasSuffix(raw, `'`) {
+				return nil, fmt.Errorf("line %d: unterminated quoted value for key %s", number, key)
+			}
+			p.values[key] = raw[1 : len(raw)-1]
+		case trailingBackslashes(raw)%2 == 1:
+			value.Reset()
+			value.WriteString(raw[:len(raw)-1])
+			continued = true
+		default:
+			p.values[key] = raw
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ini: %w", err)
+	}
+
+	// The last line may be continued at the end of the document.
+	if continued {
+		p.values[key] = value.String()
+	}
+
+	return p, nil
+}
+
+// Retrieve will return the value of the entry whose key, prefixed by the name
+// of its section, matches the key, e.g. `server.addr` for the `addr` entry of
+// the `[server]` section.
+func (p *INIProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	value, found = p.values[key]
+	return value, found, nil
+}
+
+// Name of the provider.
+func (p *INIProvider) Name() string {
+	return "ini"
+}
+
+// Priority of the provider, PriorityFile.
+func (p *INIProvider) Priority() int {
+	return PriorityFile
+}
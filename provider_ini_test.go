@@ -0,0 +1,118 @@
+package zconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestINIProvider(t *testing.T) {
+	content := `; This is a comment
+# This is another comment
+debug = true
+
+[server]
+addr = :8080
+timeout: 5s
+path = c:\windows\temp
+quoted = "  padded  "
+escaped = "say \"hi\"\tcaf\u00e9"
+literal = 'no \t escape'
+hosts = foo, \
+        bar, \
+        baz
+empty =
+
+[server.tls]
+enabled = true
+`
+
+	provider, err := NewINIProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+		found    bool
+	}{
+		{"debug", "true", true},
+		{"server.addr", ":8080", true},
+		{"server.timeout", "5s", true},
+		{"server.path", `c:\windows\temp`, true},
+		{"server.quoted", "  padded  ", true},
+		{"server.escaped", "say \"hi\"\tcafé", true},
+		{"server.literal", `no \t escape`, true},
+		{"server.hosts", "foo, bar, baz", true},
+		{"server.empty", "", true},
+		{"server.tls.enabled", "true", true},
+		{"addr", "", false},
+		{"nonexistent", "", false},
+	}
+
+	for _, test := range tests {
+		value, found, err := provider.Retrieve(test.key)
+		if err != nil {
+			t.Errorf("Unexpected error for key %s: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found {
+			t.Errorf("For key %s: expected found=%v, got found=%v", test.key, test.found, found)
+			continue
+		}
+
+		if found && value != test.expected {
+			t.Errorf("For key %s: expected value=%q, got value=%q", test.key, test.expected, value)
+		}
+	}
+}
+
+func TestNewINIProvider(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.ini")
+		err := os.WriteFile(path, []byte("[foo]\nbar = baz\n"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test ini file: %v", err)
+		}
+
+		provider, err := NewINIProvider(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		value, found, _ := provider.Retrieve("foo.bar")
+		if !found || value != "baz" {
+			t.Errorf("Expected 'baz', got %#v (found=%v)", value, found)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewINIProvider("/nonexistent/path/config.ini")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	for name, content := range map[string]string{
+		"unterminated section": "[foo\nbar = baz\n",
+		"empty section":        "[]\nbar = baz\n",
+		"missing separator":    "[foo]\nbar\n",
+		"empty key":            "[foo]\n= baz\n",
+		"unterminated quote":   "[foo]\nbar = \"baz\n",
+		"escaped quote":        "[foo]\nbar = \"baz\\\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewINIProviderFromReader(strings.NewReader(content))
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+
+			if !strings.Contains(err.Error(), "line ") {
+				t.Fatalf("expected the error to include the line, got: %v", err)
+			}
+		})
+	}
+}
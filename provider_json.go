@@ -0,0 +1,70 @@
+package zconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A Provider that implements the repository.Provider interface for JSON
+// documents.
+type JSONProvider struct {
+	tree interface{}
+}
+
+// NewJSONProvider creates a provider that loads the JSON document at the
+// given path.
+func NewJSONProvider(path string) (*JSONProvider, error) {
+	return loadFile(path, "json", NewJSONProviderFromReader)
+}
+
+// NewJSONProviderFromReader creates a provider that loads the JSON document
+// read from r. An empty document results in an empty provider, and data
+// following the document is an error.
+func NewJSONProviderFromReader(r io.Reader) (*JSONProvider, error) {
+	p := new(JSONProvider)
+
+	// Keep the numbers as json.Number so the integers aren't rounded to
+	// the precision of a float64.
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	err := decoder.Decode(&p.tree)
+	if errors.Is(err, io.EOF) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding json: %w", err)
+	}
+
+	var trailing interface{}
+	err = decoder.Decode(&trailing)
+	if !errors.Is(err, io.EOF) {
+		if err == nil {
+			err = errors.New("unexpected data after the document")
+		}
+		return nil, fmt.Errorf("decoding json: %w", err)
+	}
+
+	return p, nil
+}
+
+// Retrieve will return the value found by walking the nested objects of the
+// document along the dot-separated parts of the key. Values are returned as
+// decoded by encoding/json with numbers kept as text, i.e. json.Number, bool,
+// string, []interface{} or map[string]interface{}.
+func (p *JSONProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	value, found = lookupKey(p.tree, key)
+	return value, found, nil
+}
+
+// Name of the provider.
+func (p *JSONProvider) Name() string {
+	return "json"
+}
+
+// Priority of the provider, PriorityFile.
+func (p *JSONProvider) Priority() int {
+	return PriorityFile
+}
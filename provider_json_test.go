@@ -0,0 +1,175 @@
+package zconfig
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONProvider(t *testing.T) {
+	content := `{
+	"server": {
+		"addr": ":8080",
+		"tls": {"enabled": true}
+	},
+	"workers": 4,
+	"hosts": ["foo", "bar"],
+	"labels": {"env": "prod"},
+	"empty": null
+}`
+
+	provider, err := NewJSONProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected interface{}
+		found    bool
+	}{
+		{"server.addr", ":8080", true},
+		{"server.tls.enabled", true, true},
+		{"workers", json.Number("4"), true},
+		{"hosts", []interface{}{"foo", "bar"}, true},
+		{"labels", map[string]interface{}{"env": "prod"}, true},
+		{"labels.env", "prod", true},
+		{"empty", nil, false},
+		{"server.port", nil, false},
+		{"hosts.foo", nil, false},
+		{"nonexistent", nil, false},
+	}
+
+	for _, test := range tests {
+		value, found, err := provider.Retrieve(test.key)
+		if err != nil {
+			t.Errorf("Unexpected error for key %s: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found {
+			t.Errorf("For key %s: expected found=%v, got found=%v", test.key, test.found, found)
+			continue
+		}
+
+		if found && !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("For key %s: expected value=%#v, got value=%#v", test.key, test.expected, value)
+		}
+	}
+}
+
+func TestNewJSONProvider(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(path, []byte(`{"foo": {"bar": "baz"}}`), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test json file: %v", err)
+		}
+
+		provider, err := NewJSONProvider(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		value, found, _ := provider.Retrieve("foo.bar")
+		if !found || value != "baz" {
+			t.Errorf("Expected 'baz', got %#v (found=%v)", value, found)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewJSONProvider("/nonexistent/path/config.json")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("malformed document", func(t *testing.T) {
+		_, err := NewJSONProviderFromReader(strings.NewReader(`{"foo": `))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		_, err := NewJSONProviderFromReader(strings.NewReader(`{"foo": 1} garbage`))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
+
+func TestJSONProviderConfigure(t *testing.T) {
+	content := `{
+	"server": {
+		"addr": ":8080",
+		"id": 9007199254740993,
+		"workers": 4,
+		"ratio": 0.75,
+		"timeout": "5s",
+		"debug": true,
+		"ports": [80, 443],
+		"labels": {"env": "prod"},
+		"limits": {"read": 10, "write": 20},
+		"extra": {"foo": [1, "bar"]}
+	}
+}`
+
+	provider, err := NewJSONProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var repository Repository
+	repository.AddProviders(provider)
+	repository.AddParsers(ParseString, ParseNative)
+
+	var s struct {
+		Server struct {
+			Addr    string                 `key:"addr"`
+			ID      int64                  `key:"id"`
+			Workers int                    `key:"workers"`
+			Ratio   float32                `key:"ratio"`
+			Timeout time.Duration          `key:"timeout"`
+			Debug   bool                   `key:"debug"`
+			Ports   []uint16               `key:"ports"`
+			Labels  map[string]string      `key:"labels"`
+			Limits  map[string]int         `key:"limits"`
+			Extra   map[string]interface{} `key:"extra"`
+		} `key:"server"`
+	}
+
+	err = NewProcessor(repository.Hook).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Server.Addr != ":8080" || s.Server.Workers != 4 || s.Server.Ratio != 0.75 || s.Server.Timeout != 5*time.Second || !s.Server.Debug {
+		t.Errorf("unexpected configuration: %+v", s.Server)
+	}
+
+	if s.Server.ID != 9007199254740993 {
+		t.Errorf("expected the id to keep its precision, got %d", s.Server.ID)
+	}
+
+	if !reflect.DeepEqual(s.Server.Ports, []uint16{80, 443}) {
+		t.Errorf("unexpected ports: %v", s.Server.Ports)
+	}
+
+	if !reflect.DeepEqual(s.Server.Labels, map[string]string{"env": "prod"}) {
+		t.Errorf("unexpected labels: %v", s.Server.Labels)
+	}
+
+	if !reflect.DeepEqual(s.Server.Limits, map[string]int{"read": 10, "write": 20}) {
+		t.Errorf("unexpected limits: %v", s.Server.Limits)
+	}
+
+	if !reflect.DeepEqual(s.Server.Extra, map[string]interface{}{"foo": []interface{}{json.Number("1"), "bar"}}) {
+		t.Errorf("unexpected extra: %v", s.Server.Extra)
+	}
+}
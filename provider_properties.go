@@ -0,0 +1,215 @@
+package zconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// A Provider that implements the repository.Provider interface for Java
+// .properties files.
+type PropertiesProvider struct {
+	values map[string]string
+}
+
+// NewPropertiesProvider creates a provider that loads the .properties file at
+// the given path.
+func NewPropertiesProvider(path string) (*PropertiesProvider, error) {
+	return loadFile(path, "properties", NewPropertiesProviderFromReader)
+}
+
+// NewPropertiesProviderFromReader creates a provider that loads the
+// .properties document read from r, following the format of the Java
+// Properties.load method: `key=value`, `key:value` or `key value` entries,
+// `#` and `!` comments, backslash-terminated lines continued on the next one,
+// and escape sequences (including `\uXXXX` Unicode escapes) in both keys and
+// values.
+func NewPropertiesProviderFromReader(r io.Reader) (*PropertiesProvider, error) {
+	p := &PropertiesProvider{
+		values: make(map[string]string),
+	}
+
+	var (
+		scanner   = bufio.NewScanner(r)
+		logical   strings.Builder
+		number    = 0
+		start     = 0
+		continued = false
+	)
+	for scanner.Scan() {
+		number++
+
+		// Leading whitespace is ignored, on the first line of an entry as
+		// well as on its continuation lines.
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+
+		if !continued {
+			// Skip empty lines and comments
+			if line == "" || line[0] == '#' || line[0] == '!' {
+				continue
+			}
+			start = number
+		}
+
+		// A line ending with an odd number of backslashes is continued on
+		// the next one.
+		continued = trailingBackslashes(line)%2 == 1
+		if continued {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+
+		err := p.parseEntry(logical.String())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+		logical.Reset()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading properties: %w", err)
+	}
+
+	// The last line may be continued at the end of the document.
+	if continued {
+		err := p.parseEntry(logical.String())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+	}
+
+	return p, nil
+}
+
+// parseEntry parses a logical line into a key and its value.
+func (p *PropertiesProvider) parseEntry(line string) (err error) {
+	// The key ends at the first unescaped separator or whitespace.
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) != -1 {
+			end = i
+			break
+		}
+	}
+
+	// The separator is surrounded by optional whitespace, and may itself be
+	// whitespace only.
+	value := strings.TrimLeft(line[end:], " \t\f")
+	if value != "" && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], " \t\f")
+	}
+
+	key, err := unescapeString(line[:end])
+	if err != nil {
+		return fmt.Errorf("parsing key: %w", err)
+	}
+
+	value, err = unescapeString(value)
+	if err != nil {
+		return fmt.Errorf("parsing value for key %s: %w", key, err)
+	}
+
+	p.values[key] = value
+	return nil
+}
+
+// trailingBackslashes counts the number of backslashes at the end of s.
+func trailingBackslashes(s string) (count int) {
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		count++
+	}
+	return count
+}
+
+// unescapeString replaces the escape sequences of s: `\t`, `\n`, `\r`, `\f`
+// and `\uXXXX` (UTF-16 surrogate pairs are combined), any other escaped
+// character standing for itself.
+func unescapeString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, err := parseUnicodeEscape(s[i+1:])
+			if err != nil {
+				return "", err
+			}
+			i += 4
+
+			// Combine surrogate pairs into a single rune.
+			if utf16.IsSurrogate(r) && strings.HasPrefix(s[i+1:], `\u`) {
+				r2, err := parseUnicodeEscape(s[i+3:])
+				if err == nil {
+					if d := utf16.DecodeRune(r, r2); d != unicode.ReplacementChar {
+						r = d
+						i += 6
+					}
+				}
+			}
+
+			b.WriteRune(r)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), nil
+}
+
+// parseUnicodeEscape parses the four hexadecimal digits of a `\uXXXX` escape
+// sequence at the start of s.
+func parseUnicodeEscape(s string) (rune, error) {
+	if len(s) < 4 {
+		return 0, fmt.Errorf("malformed \\uxxxx escape sequence")
+	}
+
+	v, err := strconv.ParseUint(s[:4], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("malformed \\uxxxx escape sequence %q", `\u`+s[:4])
+	}
+
+	return rune(v), nil
+}
+
+// Retrieve will return the value of the entry with the exact same key, e.g.
+// `server.addr` for the `server.addr=:80` entry.
+func (p *PropertiesProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	value, found = p.values[key]
+	return value, found, nil
+}
+
+// Name of the provider.
+func (p *PropertiesProvider) Name() string {
+	return "properties"
+}
+
+// Priority of the provider, PriorityFile.
+func (p *PropertiesProvider) Priority() int {
+	return PriorityFile
+}
@@ -0,0 +1,114 @@
+package zconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPropertiesProvider(t *testing.T) {
+	content := `# This is a comment
+! This is another comment
+server.addr=:8080
+server.timeout : 5s
+server.name   my server
+database.url = jdbc:postgresql://localhost/test
+empty.value=
+empty.key.only
+path=c:\\windows\\temp
+tabs=col1\tcol2
+escaped\=key=value
+escaped\ space=value
+unicode=caf\u00e9
+surrogate=\ud83d\ude00
+hosts=foo, \
+      bar, \
+      baz
+odd.backslashes=foo\\
+next=bar
+`
+
+	provider, err := NewPropertiesProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+		found    bool
+	}{
+		{"server.addr", ":8080", true},
+		{"server.timeout", "5s", true},
+		{"server.name", "my server", true},
+		{"database.url", "jdbc:postgresql://localhost/test", true},
+		{"empty.value", "", true},
+		{"empty.key.only", "", true},
+		{"path", `c:\windows\temp`, true},
+		{"tabs", "col1\tcol2", true},
+		{"escaped=key", "value", true},
+		{"escaped space", "value", true},
+		{"unicode", "café", true},
+		{"surrogate", "😀", true},
+		{"hosts", "foo, bar, baz", true},
+		{"odd.backslashes", `foo\`, true},
+		{"next", "bar", true},
+		{"nonexistent", "", false},
+	}
+
+	for _, test := range tests {
+		value, found, err := provider.Retrieve(test.key)
+		if err != nil {
+			t.Errorf("Unexpected error for key %s: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found {
+			t.Errorf("For key %s: expected found=%v, got found=%v", test.key, test.found, found)
+			continue
+		}
+
+		if found && value != test.expected {
+			t.Errorf("For key %s: expected value=%q, got value=%q", test.key, test.expected, value)
+		}
+	}
+}
+
+func TestNewPropertiesProvider(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.properties")
+		err := os.WriteFile(path, []byte("foo.bar=baz\r\n"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test properties file: %v", err)
+		}
+
+		provider, err := NewPropertiesProvider(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		value, found, _ := provider.Retrieve("foo.bar")
+		if !found || value != "baz" {
+			t.Errorf("Expected 'baz', got %#v (found=%v)", value, found)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewPropertiesProvider("/nonexistent/path/config.properties")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("malformed unicode escape", func(t *testing.T) {
+		_, err := NewPropertiesProviderFromReader(strings.NewReader("foo=bar\nbaz=\\u00zz\n"))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+
+		if !strings.Contains(err.Error(), "line 2") {
+			t.Fatalf("expected the error to include the line, got: %v", err)
+		}
+	})
+}
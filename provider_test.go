@@ -1,5 +1,13 @@
 package zconfig
 
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
 type TestProvider struct {
 	name   string
 	values map[string]string
@@ -17,3 +25,177 @@ func (TestProvider) Priority() int {
 func (p TestProvider) Name() string {
 	return p.name
 }
+
+func TestEnvProviderFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	secret := filepath.Join(tmpDir, "secret")
+	if err := os.WriteFile(secret, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	t.Setenv("ZCONFIG_TEST_PLAIN", "plain")
+	t.Setenv("ZCONFIG_TEST_PLAIN_FILE", secret)
+	t.Setenv("ZCONFIG_TEST_SECRET_FILE", secret)
+	t.Setenv("ZCONFIG_TEST_MISSING_FILE", filepath.Join(tmpDir, "missing"))
+
+	provider := NewEnvProvider()
+
+	tests := []struct {
+		key      string
+		expected string
+		source   string
+		found    bool
+		err      bool
+	}{
+		{"zconfig.test.plain", "plain", "", true, false},
+		{"zconfig.test.secret", "from-file", ProviderEnvFile, true, false},
+		{"zconfig.test.missing", "", ProviderEnvFile, false, true},
+		{"zconfig.test.nonexistent", "", "", false, false},
+	}
+
+	for _, test := range tests {
+		value, source, found, err := provider.RetrieveSource(test.key)
+		if (err != nil) != test.err {
+			t.Errorf("For key %s: unexpected error: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found || source != test.source {
+			t.Errorf("For key %s: expected found=%v from %q, got found=%v from %q", test.key, test.found, test.source, found, source)
+			continue
+		}
+
+		if found && value != test.expected {
+			t.Errorf("For key %s: expected value=%q, got value=%q", test.key, test.expected, value)
+		}
+	}
+
+	var repository Repository
+	repository.AddProviders(provider)
+
+	value, name, found, err := repository.Retrieve("zconfig.test.secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found || value != "from-file" || name != ProviderEnvFile {
+		t.Errorf("Expected 'from-file' from %s, got %q from %s (found=%v)", ProviderEnvFile, value, name, found)
+	}
+
+	value, name, _, _ = repository.Retrieve("zconfig.test.plain")
+	if value != "plain" || name != "env" {
+		t.Errorf("Expected 'plain' from env, got %q from %s", value, name)
+	}
+
+	_, _, _, err = repository.Retrieve("zconfig.test.missing")
+	if err == nil || !strings.Contains(err.Error(), "ZCONFIG_TEST_MISSING_FILE") {
+		t.Errorf("Expected an error naming the variable, got: %v", err)
+	}
+}
+
+func TestEnvProviderPrefix(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		key      string
+		expected string
+	}{
+		{"", "server.addr", "SERVER_ADDR"},
+		{"myapp", "server.addr", "MYAPP_SERVER_ADDR"},
+		{"MYAPP_", "server.addr", "MYAPP_SERVER_ADDR"},
+		{"my-app", "api-key", "MY_APP_API_KEY"},
+	}
+
+	for _, test := range tests {
+		result := NewEnvProviderWithPrefix(test.prefix).FormatKey(test.key)
+		if result != test.expected {
+			t.Errorf("FormatKey(%s) with prefix %q: expected %s, got %s", test.key, test.prefix, test.expected, result)
+		}
+	}
+
+	t.Setenv("ZCONFIG_PREFIXED_ADDR", "prefixed")
+	t.Setenv("PREFIXED_ADDR", "unprefixed")
+
+	value, found, err := NewEnvProviderWithPrefix("zconfig").Retrieve("prefixed.addr")
+	if err != nil || !found || value != "prefixed" {
+		t.Errorf("Expected 'prefixed', got %q (found=%v, err=%v)", value, found, err)
+	}
+}
+
+func TestSetEnvPrefix(t *testing.T) {
+	oldEnv := Env
+	defer SetEnvPrefix(oldEnv.Prefix)
+
+	t.Setenv("ZCONFIG_PREFIXED_ADDR", "prefixed")
+	t.Setenv("PREFIXED_ADDR", "unprefixed")
+
+	SetEnvPrefix("zconfig")
+
+	if Env.FormatKey("prefixed.addr") != "ZCONFIG_PREFIXED_ADDR" {
+		t.Errorf("Expected the default env provider to be prefixed, got %s", Env.FormatKey("prefixed.addr"))
+	}
+
+	value, provider, found, err := DefaultRepository.Retrieve("prefixed.addr")
+	if err != nil || !found || value != "prefixed" || provider != "env" {
+		t.Errorf("Expected 'prefixed' from env, got %q from %s (found=%v, err=%v)", value, provider, found, err)
+	}
+}
+
+func TestNameOverrides(t *testing.T) {
+	t.Setenv("ZCONFIG_TEST_PORT", "8080")
+	t.Setenv("APP_SERVER_HOST", "localhost")
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, []byte("ZCONFIG_TEST_URL=postgres://localhost\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	var repository Repository
+	repository.AddProviders(
+		&ArgsProvider{Args: map[string]string{"verbose": "true"}},
+		NewEnvProviderWithPrefix("app"),
+		NewDotenvProviderWithPath(envFile),
+	)
+	repository.AddParsers(ParseString)
+
+	var s struct {
+		Server struct {
+			Host    string `key:"host"`
+			Port    int    `key:"port" env:"ZCONFIG_TEST_PORT"`
+			URL     string `key:"url" env:"ZCONFIG_TEST_URL"`
+			Verbose bool   `key:"verbose" flag:"verbose"`
+		} `key:"server"`
+	}
+
+	var fields = make(map[string]*Field)
+	collect := func(ctx context.Context, f *Field) error {
+		fields[f.Path] = f
+		return nil
+	}
+
+	err := NewProcessor(repository.Hook, collect).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Server.Host != "localhost" || s.Server.Port != 8080 || s.Server.URL != "postgres://localhost" || !s.Server.Verbose {
+		t.Errorf("unexpected configuration: %+v", s.Server)
+	}
+
+	for path, expected := range map[string]string{
+		"$.Server.Host":    "APP_SERVER_HOST",
+		"$.Server.Port":    "ZCONFIG_TEST_PORT",
+		"$.Server.Verbose": "APP_SERVER_VERBOSE",
+	} {
+		if env := NewEnvProviderWithPrefix("app").FormatFieldKey(fields[path]); env != expected {
+			t.Errorf("For field %s: expected env %s, got %s", path, expected, env)
+		}
+	}
+
+	for path, expected := range map[string]string{
+		"$.Server.Host":    "server.host",
+		"$.Server.Verbose": "verbose",
+	} {
+		if flag := Args.FormatFieldKey(fields[path]); flag != expected {
+			t.Errorf("For field %s: expected flag %s, got %s", path, expected, flag)
+		}
+	}
+}
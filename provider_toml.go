@@ -0,0 +1,85 @@
+package zconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A Provider that implements the repository.Provider interface for TOML
+// documents.
+type TOMLProvider struct {
+	tree interface{}
+}
+
+// NewTOMLProvider creates a provider that loads the TOML document at the
+// given path.
+func NewTOMLProvider(path string) (*TOMLProvider, error) {
+	return loadFile(path, "toml", NewTOMLProviderFromReader)
+}
+
+// NewTOMLProviderFromReader creates a provider that loads the TOML document
+// read from r. Syntax errors are reported with their position in the
+// document.
+func NewTOMLProviderFromReader(r io.Reader) (*TOMLProvider, error) {
+	var tree map[string]interface{}
+
+	_, err := toml.NewDecoder(r).Decode(&tree)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, fmt.Errorf("decoding toml: line %d, column %d: %s", perr.Position.Line, perr.Position.Col, perr.Message)
+		}
+		return nil, fmt.Errorf("decoding toml: %w", err)
+	}
+
+	return &TOMLProvider{tree: normalizeTOML(tree)}, nil
+}
+
+// normalizeTOML converts the arrays of tables of a decoded document to plain
+// lists so they can be walked like any other list.
+func normalizeTOML(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			value[k] = normalizeTOML(v)
+		}
+		return value
+	case []map[string]interface{}:
+		list := make([]interface{}, 0, len(value))
+		for _, v := range value {
+			list = append(list, normalizeTOML(v))
+		}
+		return list
+	case []interface{}:
+		for i, v := range value {
+			value[i] = normalizeTOML(v)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// Retrieve will return the value found by walking the tables of the document
+// along the dot-separated parts of the key, e.g. `server.addr` for the `addr`
+// key of the `[server]` table. Elements of arrays (of tables) are addressed
+// by their index, e.g. `servers.0.addr`. Values are returned as decoded, i.e.
+// int64, float64, bool, string, time.Time, []interface{} or
+// map[string]interface{}.
+func (p *TOMLProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	value, found = lookupKey(p.tree, key)
+	return value, found, nil
+}
+
+// Name of the provider.
+func (p *TOMLProvider) Name() string {
+	return "toml"
+}
+
+// Priority of the provider, PriorityFile.
+func (p *TOMLProvider) Priority() int {
+	return PriorityFile
+}
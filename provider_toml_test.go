@@ -0,0 +1,176 @@
+package zconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOMLProvider(t *testing.T) {
+	content := `
+workers = 4
+hosts = ["foo", "bar"]
+
+[server]
+addr = ":8080"
+started = 2019-01-11T15:01:31Z
+
+[server.tls]
+enabled = true
+
+[[backends]]
+addr = "10.0.0.1:80"
+weight = 1
+
+[[backends]]
+addr = "10.0.0.2:80"
+weight = 2
+`
+
+	provider, err := NewTOMLProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected interface{}
+		found    bool
+	}{
+		{"workers", int64(4), true},
+		{"hosts", []interface{}{"foo", "bar"}, true},
+		{"hosts.1", "bar", true},
+		{"server.addr", ":8080", true},
+		{"server.started", time.Date(2019, time.January, 11, 15, 01, 31, 000, time.UTC), true},
+		{"server.tls.enabled", true, true},
+		{"backends.0.addr", "10.0.0.1:80", true},
+		{"backends.1.weight", int64(2), true},
+		{"backends.2.addr", nil, false},
+		{"backends.foo", nil, false},
+		{"server.port", nil, false},
+		{"nonexistent", nil, false},
+	}
+
+	for _, test := range tests {
+		value, found, err := provider.Retrieve(test.key)
+		if err != nil {
+			t.Errorf("Unexpected error for key %s: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found {
+			t.Errorf("For key %s: expected found=%v, got found=%v", test.key, test.found, found)
+			continue
+		}
+
+		if found && !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("For key %s: expected value=%#v, got value=%#v", test.key, test.expected, value)
+		}
+	}
+
+	value, _, _ := provider.Retrieve("backends")
+	if list, ok := value.([]interface{}); !ok || len(list) != 2 {
+		t.Errorf("Expected a list of 2 tables for backends, got %#v", value)
+	}
+}
+
+func TestNewTOMLProvider(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		err := os.WriteFile(path, []byte("[foo]\nbar = \"baz\"\n"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test toml file: %v", err)
+		}
+
+		provider, err := NewTOMLProvider(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		value, found, _ := provider.Retrieve("foo.bar")
+		if !found || value != "baz" {
+			t.Errorf("Expected 'baz', got %#v (found=%v)", value, found)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewTOMLProvider("/nonexistent/path/config.toml")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("malformed document", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		err := os.WriteFile(path, []byte("[foo]\nbar = \"baz\"\nqux = \n"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test toml file: %v", err)
+		}
+
+		_, err = NewTOMLProvider(path)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+
+		if !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), "line 3") {
+			t.Fatalf("expected the error to include the file and line, got: %v", err)
+		}
+	})
+}
+
+func TestTOMLProviderConfigure(t *testing.T) {
+	content := `
+[server]
+addr = ":8080"
+workers = 4
+ratio = 0.5
+ports = [80, 443]
+started = 2019-01-11T15:01:31Z
+label = 2019-01-11T15:01:31Z
+`
+
+	provider, err := NewTOMLProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var repository Repository
+	repository.AddProviders(provider)
+	repository.AddParsers(ParseString, ParseNative)
+
+	var s struct {
+		Server struct {
+			Addr    string    `key:"addr"`
+			Workers int       `key:"workers"`
+			Ratio   float64   `key:"ratio"`
+			Ports   []int     `key:"ports"`
+			Started time.Time `key:"started"`
+			Label   string    `key:"label"`
+		} `key:"server"`
+	}
+
+	err = NewProcessor(repository.Hook).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Server.Addr != ":8080" || s.Server.Workers != 4 || s.Server.Ratio != 0.5 {
+		t.Errorf("unexpected configuration: %+v", s.Server)
+	}
+
+	if !reflect.DeepEqual(s.Server.Ports, []int{80, 443}) {
+		t.Errorf("unexpected ports: %v", s.Server.Ports)
+	}
+
+	if !s.Server.Started.Equal(time.Date(2019, time.January, 11, 15, 01, 31, 000, time.UTC)) {
+		t.Errorf("unexpected start date: %v", s.Server.Started)
+	}
+
+	if s.Server.Label != "2019-01-11T15:01:31Z" {
+		t.Errorf("unexpected label: %v", s.Server.Label)
+	}
+}
@@ -0,0 +1,53 @@
+package zconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A Provider that implements the repository.Provider interface for YAML
+// documents.
+type YAMLProvider struct {
+	tree interface{}
+}
+
+// NewYAMLProvider creates a provider that loads the YAML document at the
+// given path.
+func NewYAMLProvider(path string) (*YAMLProvider, error) {
+	return loadFile(path, "yaml", NewYAMLProviderFromReader)
+}
+
+// NewYAMLProviderFromReader creates a provider that loads the YAML document
+// read from r. An empty document results in an empty provider.
+func NewYAMLProviderFromReader(r io.Reader) (*YAMLProvider, error) {
+	p := new(YAMLProvider)
+
+	err := yaml.NewDecoder(r).Decode(&p.tree)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decoding yaml: %w", err)
+	}
+
+	return p, nil
+}
+
+// Retrieve will return the value found by walking the nested mappings of the
+// document along the dot-separated parts of the key, e.g. `server.addr` for
+// the `addr` entry of the `server` mapping. Values are returned as decoded,
+// e.g. int, bool, float64, string or []interface{}.
+func (p *YAMLProvider) Retrieve(key string) (value interface{}, found bool, err error) {
+	value, found = lookupKey(p.tree, key)
+	return value, found, nil
+}
+
+// Name of the provider.
+func (p *YAMLProvider) Name() string {
+	return "yaml"
+}
+
+// Priority of the provider, PriorityFile.
+func (p *YAMLProvider) Priority() int {
+	return PriorityFile
+}
@@ -0,0 +1,160 @@
+package zconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYAMLProvider(t *testing.T) {
+	content := `
+server:
+  addr: ":8080"
+  timeout: 5s
+  tls:
+    enabled: true
+workers: 4
+ratio: 0.5
+hosts:
+  - foo
+  - bar
+empty:
+`
+
+	provider, err := NewYAMLProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected interface{}
+		found    bool
+	}{
+		{"server.addr", ":8080", true},
+		{"server.timeout", "5s", true},
+		{"server.tls.enabled", true, true},
+		{"workers", 4, true},
+		{"ratio", 0.5, true},
+		{"hosts", []interface{}{"foo", "bar"}, true},
+		{"empty", nil, false},
+		{"server.port", nil, false},
+		{"workers.count", nil, false},
+		{"nonexistent", nil, false},
+	}
+
+	for _, test := range tests {
+		value, found, err := provider.Retrieve(test.key)
+		if err != nil {
+			t.Errorf("Unexpected error for key %s: %v", test.key, err)
+			continue
+		}
+
+		if found != test.found {
+			t.Errorf("For key %s: expected found=%v, got found=%v", test.key, test.found, found)
+			continue
+		}
+
+		if found && !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("For key %s: expected value=%#v, got value=%#v", test.key, test.expected, value)
+		}
+	}
+}
+
+func TestNewYAMLProvider(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("foo:\n  bar: baz\n"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test yaml file: %v", err)
+		}
+
+		provider, err := NewYAMLProvider(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		value, found, _ := provider.Retrieve("foo.bar")
+		if !found || value != "baz" {
+			t.Errorf("Expected 'baz', got %#v (found=%v)", value, found)
+		}
+	})
+
+	t.Run("empty document", func(t *testing.T) {
+		provider, err := NewYAMLProviderFromReader(strings.NewReader(""))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, found, _ := provider.Retrieve("foo")
+		if found {
+			t.Error("Expected not to find any key in empty document")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewYAMLProvider("/nonexistent/path/config.yaml")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("malformed document", func(t *testing.T) {
+		_, err := NewYAMLProviderFromReader(strings.NewReader("foo: [bar"))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
+
+func TestYAMLProviderConfigure(t *testing.T) {
+	content := `
+server:
+  addr: ":8080"
+  workers: 4
+  debug: true
+  hosts: [foo, bar]
+  ports: [80, 443]
+  timeout:
+`
+
+	provider, err := NewYAMLProviderFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var repository Repository
+	repository.AddProviders(provider)
+	repository.AddParsers(ParseString, ParseNative)
+
+	var s struct {
+		Server struct {
+			Addr    string   `key:"addr"`
+			Workers int      `key:"workers"`
+			Debug   bool     `key:"debug"`
+			Hosts   []string `key:"hosts"`
+			Ports   []int    `key:"ports"`
+			Timeout string   `key:"timeout" default:"30s"`
+		} `key:"server"`
+	}
+
+	err = NewProcessor(repository.Hook).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Server.Addr != ":8080" || s.Server.Workers != 4 || !s.Server.Debug || s.Server.Timeout != "30s" {
+		t.Errorf("unexpected configuration: %+v", s.Server)
+	}
+
+	if !reflect.DeepEqual(s.Server.Hosts, []string{"foo", "bar"}) {
+		t.Errorf("unexpected hosts: %v", s.Server.Hosts)
+	}
+
+	if !reflect.DeepEqual(s.Server.Ports, []int{80, 443}) {
+		t.Errorf("unexpected ports: %v", s.Server.Ports)
+	}
+}
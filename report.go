@@ -0,0 +1,131 @@
+package zconfig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/hchargois/flexwriter"
+)
+
+// A ReportEntry describes the effective configuration of a field.
+type ReportEntry struct {
+	// Configuration key and path of the field.
+	Key  string `json:"key"`
+	Path string `json:"path"`
+
+	// Value of the field, formatted as with fmt.Print, or Redacted for the
+	// secret fields.
+	Value string `json:"value"`
+
+	// Name of the provider that supplied the value, and the alternative key
+	// it was retrieved with, if any.
+	Provider string `json:"provider"`
+	Alias    string `json:"alias,omitempty"`
+
+	// Whether the default value of the field was used, and whether its value
+	// is redacted.
+	Default bool `json:"default"`
+	Secret  bool `json:"secret,omitempty"`
+}
+
+// A Report lists the effective configuration of the configurable fields,
+// sorted by key, e.g. to log the configuration a service is running with.
+// It can be serialized to JSON, or formatted as a table with WriteTable.
+type Report []ReportEntry
+
+// Dump returns the report of the configurable fields, once configured. The
+// fields of the optional structs left unset are omitted.
+func Dump(fields []*Field) (report Report) {
+	for _, f := range fields {
+		if f.Configurable && !f.inUnsetStruct() {
+			report = append(report, newReportEntry(f))
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Key < report[j].Key
+	})
+
+	return report
+}
+
+// Hook adds the configurable fields to the report. It must be executed after
+// the hook of the repository, e.g.:
+//
+//	var report zconfig.Report
+//	zconfig.AddHooks(report.Hook)
+func (r *Report) Hook(ctx context.Context, f *Field) error {
+	if !f.Configurable || f.inUnsetStruct() {
+		return nil
+	}
+
+	i := sort.Search(len(*r), func(i int) bool {
+		return (*r)[i].Key > f.ConfigurationKey
+	})
+	*r = append(*r, ReportEntry{})
+	copy((*r)[i+1:], (*r)[i:])
+	(*r)[i] = newReportEntry(f)
+
+	return nil
+}
+
+func newReportEntry(f *Field) ReportEntry {
+	entry := ReportEntry{
+		Key:      f.ConfigurationKey,
+		Path:     f.Path,
+		Provider: f.Provider,
+		Alias:    f.Alias,
+		Default:  f.Provider == ProviderDefault,
+		Secret:   f.IsSecret(),
+	}
+
+	val := f.Value
+	if val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	switch {
+	case entry.Secret:
+		entry.Value = Redacted
+	case val.Kind() == reflect.Ptr:
+		entry.Value = "<nil>"
+	default:
+		entry.Value = fmt.Sprint(val.Interface())
+	}
+
+	return entry
+}
+
+// WriteTable writes the report to w as a table of the keys, values and
+// providers.
+func (r Report) WriteTable(w io.Writer) error {
+	table := flexwriter.New()
+	table.SetOutput(w)
+	table.SetColumns(
+		flexwriter.Rigid{},      // key
+		flexwriter.Shrinkable{}, // value
+		flexwriter.Rigid{},      // provider
+	)
+
+	table.WriteRow("KEY", "VALUE", "PROVIDER")
+	for _, entry := range r {
+		provider := entry.Provider
+		if entry.Alias != "" {
+			provider += " (" + entry.Alias + ")"
+		}
+		table.WriteRow(entry.Key, entry.Value, provider)
+	}
+
+	return table.Flush()
+}
+
+// String formats the report as a table.
+func (r Report) String() string {
+	var buf bytes.Buffer
+	_ = r.WriteTable(&buf)
+	return buf.String()
+}
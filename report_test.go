@@ -0,0 +1,107 @@
+package zconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	var repository Repository
+	repository.AddProviders(TestProvider{name: "test", values: map[string]string{
+		"addr":          ":8080",
+		"db.password":   "hunter2",
+		"db.old-engine": "postgres",
+	}})
+	repository.AddParsers(ParseString)
+
+	var s struct {
+		Addr    string        `key:"addr"`
+		Timeout time.Duration `key:"timeout" default:"5s"`
+		Port    *int          `key:"port" default:"80"`
+		DB      struct {
+			Password string `key:"password" secret:"true"`
+			Engine   string `key:"engine" alias:"old-engine"`
+		} `key:"db"`
+	}
+
+	var report Report
+	err := NewProcessor(repository.Hook, report.Hook).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Report{
+		{Key: "addr", Path: "$.Addr", Value: ":8080", Provider: "test"},
+		{Key: "db.engine", Path: "$.DB.Engine", Value: "postgres", Provider: "test", Alias: "db.old-engine"},
+		{Key: "db.password", Path: "$.DB.Password", Value: Redacted, Provider: "test", Secret: true},
+		{Key: "port", Path: "$.Port", Value: "80", Provider: ProviderDefault, Default: true},
+		{Key: "timeout", Path: "$.Timeout", Value: "5s", Provider: ProviderDefault, Default: true},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Fatalf("unexpected report:\nexpected %+v\ngot      %+v", expected, report)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		raw, err := json.Marshal(report)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Contains(string(raw), "hunter2") {
+			t.Errorf("expected the secret to be redacted, got %s", raw)
+		}
+
+		var decoded Report
+		err = json.Unmarshal(raw, &decoded)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(decoded, report) {
+			t.Errorf("unexpected decoded report: %+v", decoded)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		var output bytes.Buffer
+		err := report.WriteTable(&output)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		if len(lines) != len(report)+1 {
+			t.Fatalf("unexpected table:\n%s", output.String())
+		}
+		for i, expected := range []string{
+			"KEY VALUE PROVIDER",
+			"addr :8080 test",
+			"db.engine postgres test (db.old-engine)",
+			"db.password ****** test",
+			"port 80 default",
+			"timeout 5s default",
+		} {
+			if line := strings.Join(strings.Fields(lines[i]), " "); line != expected {
+				t.Errorf("line %d: expected %q, got %q", i, expected, line)
+			}
+		}
+	})
+
+	t.Run("dump", func(t *testing.T) {
+		fields := usageFields(t, &s)
+		for _, f := range fields {
+			if f.Configurable {
+				f.Provider = "test"
+			}
+		}
+
+		dump := Dump(fields)
+		if len(dump) != len(report) || dump[0].Key != "addr" || dump[0].Value != ":8080" || dump[2].Value != Redacted {
+			t.Errorf("unexpected dump: %+v", dump)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"reflect"
 	"sort"
 	"sync"
@@ -16,27 +17,92 @@ type Repository struct {
 	lock      sync.Mutex
 	providers []Provider
 	parsers   []Parser
+
+	// Logger reports the use of deprecated keys. If unset, the standard
+	// logger of the log package is used.
+	Logger Logger
 }
 
-// Register a new Provider in this repository.
+// Register a new Provider in this repository. Providers with the same priority
+// are consulted in the order they were added.
 func (r *Repository) AddProviders(providers ...Provider) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	r.providers = append(r.providers, providers...)
-	sort.Slice(r.providers, func(a, b int) bool {
+	sort.SliceStable(r.providers, func(a, b int) bool {
+		return r.providers[a].Priority() < r.providers[b].Priority()
+	})
+}
+
+// replaceProvider replaces a registered provider by another one, keeping the
+// order of the providers.
+func (r *Repository) replaceProvider(old, new Provider) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i, p := range r.providers {
+		if p == old {
+			r.providers[i] = new
+		}
+	}
+	sort.SliceStable(r.providers, func(a, b int) bool {
 		return r.providers[a].Priority() < r.providers[b].Priority()
 	})
 }
 
-// Retrieve a key from the provider, by priority order.
+// Retrieve a key from the provider, by priority order. The returned provider is
+// the name of the provider the key was found in, or the source it reported if
+// it is a SourceProvider.
 func (r *Repository) Retrieve(key string) (value interface{}, provider string, found bool, err error) {
+	return r.retrieve(func(p Provider) (interface{}, string, bool, error) {
+		if sp, ok := p.(SourceProvider); ok {
+			return sp.RetrieveSource(key)
+		}
+		value, found, err := p.Retrieve(key)
+		return value, "", found, err
+	})
+}
+
+// Exists returns true if one of the providers has a value for the key. It is
+// meant to be the Exists function of a processor using the hook of the
+// repository.
+func (r *Repository) Exists(key string) (bool, error) {
+	_, _, found, err := r.Retrieve(key)
+	return found, err
+}
+
+// RetrieveField retrieves the value of a field from the providers, by
+// priority order. It behaves like Retrieve with the field's configuration key,
+// except that the providers implementing FieldProvider are given the whole
+// field.
+func (r *Repository) RetrieveField(f *Field) (value interface{}, provider string, found bool, err error) {
+	return r.retrieve(func(p Provider) (interface{}, string, bool, error) {
+		switch p := p.(type) {
+		case FieldProvider:
+			return p.RetrieveField(f)
+		case SourceProvider:
+			return p.RetrieveSource(f.ConfigurationKey)
+		default:
+			value, found, err := p.Retrieve(f.ConfigurationKey)
+			return value, "", found, err
+		}
+	})
+}
+
+// retrieve consults the providers by priority order until one of them finds
+// a value.
+func (r *Repository) retrieve(lookup func(Provider) (interface{}, string, bool, error)) (value interface{}, provider string, found bool, err error) {
 	for _, p := range r.providers {
-		value, found, err = p.Retrieve(key)
+		var source string
+		value, source, found, err = lookup(p)
+		if source == "" {
+			source = p.Name()
+		}
+
 		if err != nil {
-			return nil, p.Name(), false, err
+			return nil, source, false, err
 		}
 		if found {
-			return value, p.Name(), true, nil
+			return value, source, true, nil
 		}
 	}
 
@@ -55,6 +121,11 @@ func (r *Repository) AddParsers(parsers ...Parser) {
 // Parse the parameter depending on the kind of the field, returning an
 // appropriately typed reflect.Value.
 func (r *Repository) Parse(raw, res interface{}) (err error) {
+	// Parse the value held by a secret.
+	if secret, ok := res.(secretValue); ok {
+		res = secret.secretTarget()
+	}
+
 	for _, p := range r.parsers {
 		err = p(raw, res)
 		if err == ErrNotParseable {
@@ -68,23 +139,55 @@ func (r *Repository) Parse(raw, res interface{}) (err error) {
 	return fmt.Errorf("no parser for type %T", res)
 }
 
+// Hook configures a field from the providers of the repository. A missing key
+// is an error, unless the field has a default value or is optional. The fields
+// with a `required_if`, `required_with` or `exclusive_group` tag are left
+// unset when missing, and must be checked by the CheckRequirements hook, which
+// the default processor executes.
 func (r *Repository) Hook(ctx context.Context, f *Field) (err error) {
 	if !f.Configurable {
+		if f.isOptional() && len(f.Children) != 0 {
+			return checkOptional(f)
+		}
 		return nil
 	}
 
-	raw, provider, found, err := r.Retrieve(f.ConfigurationKey)
+	raw, provider, found, err := r.RetrieveField(f)
 	if err != nil {
 		return fmt.Errorf("configuring field %s: retrieving key %s: %w", f.Path, f.ConfigurationKey, err)
 	}
 
+	if !found {
+		raw, provider, f.Alias, found, err = r.retrieveAlias(f)
+		if err != nil {
+			return fmt.Errorf("configuring field %s: retrieving key %s: %w", f.Path, f.Alias, err)
+		}
+		if found && f.isDeprecatedKey(f.Alias) {
+			r.logger().Printf("zconfig: key %s (from %s) is deprecated, use %s instead", f.Alias, provider, f.ConfigurationKey)
+		}
+	}
+
 	if !found {
 		def, ok := f.Tags.Lookup(TagDefault)
-		if !ok {
-			return fmt.Errorf("configuring field %s: missing key %s", f.Path, f.ConfigurationKey)
+		switch {
+		case ok:
+			raw = def
+			provider = ProviderDefault
+		case f.isOptional():
+			f.unset()
+			return nil
+		case f.isConditional():
+			// The conditional requirements are checked by CheckRequirements.
+			return nil
+		case f.hasOptionalAncestor():
+			// The descendants of an optional struct are checked along with it.
+			return nil
+		default:
+			return fmt.Errorf("configuring field %s: %w", f.Path, &MissingKeyError{
+				Path: f.Path,
+				Key:  f.ConfigurationKey,
+			})
 		}
-		raw = def
-		provider = ProviderDefault
 	}
 
 	var val = f.Value
@@ -94,10 +197,70 @@ func (r *Repository) Hook(ctx context.Context, f *Field) (err error) {
 
 	err = r.Parse(raw, val.Interface())
 	if err != nil {
-		return fmt.Errorf("configuring field %s: parsing value for key %s: %w", f.Path, f.ConfigurationKey, err)
+		return fmt.Errorf("configuring field %s: %w", f.Path, &ParseError{
+			Path:     f.Path,
+			Key:      f.ConfigurationKey,
+			Provider: provider,
+			Raw:      raw,
+			Type:     f.Value.Type(),
+			Secret:   f.IsSecret(),
+			Err:      err,
+		})
+	}
+
+	err = validateField(f, provider)
+	if err != nil {
+		return fmt.Errorf("configuring field %s: %w", f.Path, err)
 	}
 
 	f.Provider = provider
 
 	return nil
 }
+
+// retrieveAlias retrieves the value of a field from its alternative keys, the
+// aliases first and then the deprecated ones, returning the key used.
+func (r *Repository) retrieveAlias(f *Field) (raw interface{}, provider, key string, found bool, err error) {
+	for _, key = range append(append([]string{}, f.AliasKeys...), f.DeprecatedKeys...) {
+		raw, provider, found, err = r.Retrieve(key)
+		if err != nil {
+			return nil, provider, key, false, err
+		}
+		if found {
+			return raw, provider, key, true, nil
+		}
+	}
+
+	return nil, "", "", false, nil
+}
+
+// LookupField returns the raw value of a field and the name of the provider
+// supplying it, as the Hook would retrieve them (from the key of the field,
+// its alternative keys or its default value), without parsing the value nor
+// updating the field. It is meant to be the Lookup function of a processor
+// using the hook of the repository.
+func (r *Repository) LookupField(f *Field) (raw interface{}, provider string, found bool, err error) {
+	raw, provider, found, err = r.RetrieveField(f)
+	if err != nil || found {
+		return raw, provider, found, err
+	}
+
+	raw, provider, _, found, err = r.retrieveAlias(f)
+	if err != nil || found {
+		return raw, provider, found, err
+	}
+
+	if def, ok := f.Tags.Lookup(TagDefault); ok {
+		return def, ProviderDefault, true, nil
+	}
+
+	return nil, "", false, nil
+}
+
+// logger returns the logger of the repository, or the standard one.
+func (r *Repository) logger() Logger {
+	if r.Logger != nil {
+		return r.Logger
+	}
+	return log.Default()
+}
@@ -0,0 +1,77 @@
+package zconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestRepositoryAliases(t *testing.T) {
+	type Redis struct {
+		Addr    string `key:"addr" deprecated:"address"`
+		DB      int    `key:"db" alias:"database,index"`
+		Timeout string `key:"timeout" deprecated:"conn-timeout" default:"1s"`
+		Pool    int    `key:"pool" deprecated:"pool-size"`
+	}
+
+	var s struct {
+		Redis Redis `key:"redis"`
+	}
+
+	var logger testLogger
+	var repository = Repository{Logger: &logger}
+	repository.AddProviders(TestProvider{"test", map[string]string{
+		"redis.address":   "localhost:6379",
+		"redis.index":     "2",
+		"redis.pool":      "10",
+		"redis.pool-size": "20",
+	}})
+	repository.AddParsers(ParseString)
+
+	var fields = make(map[string]*Field)
+	collect := func(ctx context.Context, f *Field) error {
+		fields[f.Path] = f
+		return nil
+	}
+
+	err := NewProcessor(repository.Hook, collect).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Redis.Addr != "localhost:6379" || s.Redis.DB != 2 || s.Redis.Timeout != "1s" || s.Redis.Pool != 10 {
+		t.Errorf("unexpected configuration: %+v", s.Redis)
+	}
+
+	for path, expected := range map[string]struct {
+		alias    string
+		provider string
+	}{
+		"$.Redis.Addr":    {"redis.address", "test"},
+		"$.Redis.DB":      {"redis.index", "test"},
+		"$.Redis.Timeout": {"", ProviderDefault},
+		"$.Redis.Pool":    {"", "test"},
+	} {
+		f := fields[path]
+		if f.Alias != expected.alias || f.Provider != expected.provider {
+			t.Errorf("For field %s: expected alias %q from %s, got %q from %s", path, expected.alias, expected.provider, f.Alias, f.Provider)
+		}
+	}
+
+	if got := fields["$.Redis.DB"].AliasKeys; strings.Join(got, ",") != "redis.database,redis.index" {
+		t.Errorf("unexpected alias keys: %v", got)
+	}
+
+	if len(logger.messages) != 1 || !strings.Contains(logger.messages[0], "redis.address") || !strings.Contains(logger.messages[0], "redis.addr") {
+		t.Errorf("expected a single deprecation warning, got: %v", logger.messages)
+	}
+}
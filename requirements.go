@@ -0,0 +1,301 @@
+package zconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	TagRequiredIf     = "required_if"
+	TagRequiredWith   = "required_with"
+	TagExclusiveGroup = "exclusive_group"
+)
+
+// isConditional returns true if the field is required depending on other
+// fields, in which case the hook of the repository tolerates it to be missing
+// and CheckRequirements checks it.
+func (f *Field) isConditional() bool {
+	for _, tag := range []string{TagRequiredIf, TagRequiredWith, TagExclusiveGroup} {
+		if _, ok := f.Tags.Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isProvided returns true if the value of the field was supplied by a
+// provider, and not by its default value.
+func (f *Field) isProvided() bool {
+	return f.Provider != "" && f.Provider != ProviderDefault
+}
+
+// CheckRequirements is a hook checking the conditional requirements of the
+// fields, once they are all configured. Executed after the hook of the
+// repository, it checks that:
+//
+//   - the fields with a `required_if` tag, e.g. `tls.enabled=true`, are
+//     provided when all the comma-separated keys have the given values;
+//   - the fields with a `required_with` tag, e.g. `db.host,db.user`, are
+//     provided when any of the comma-separated keys is provided;
+//   - at most one alternative of an exclusive group is provided, and exactly
+//     one if none of them has a default value, with all its fields set.
+//
+// The keys are relative to the parent of the field, or else absolute. The
+// default value of a field satisfies its own requirements, but does not count
+// as provided for the fields requiring it nor for an exclusive group. The
+// fields of an optional struct left unset are not checked.
+//
+// The alternatives of an exclusive group are its sibling fields with the same
+// `exclusive_group` tag: either its name, e.g. `auth`, for a field being an
+// alternative on its own, or its name followed by the name of the alternative,
+// e.g. `auth:password`, for the fields forming an alternative together.
+func CheckRequirements(ctx context.Context, f *Field) error {
+	if !f.Configurable || f.inUnsetStruct() {
+		return nil
+	}
+
+	if conditions, ok := f.Tags.Lookup(TagRequiredIf); ok {
+		required, err := requiredIf(f, conditions)
+		if err != nil {
+			return fmt.Errorf("checking %s tag: %w", TagRequiredIf, err)
+		}
+		if required && f.Provider == "" {
+			return fmt.Errorf("required if %s: %w", conditions, &MissingKeyError{
+				Path: f.Path,
+				Key:  f.ConfigurationKey,
+			})
+		}
+	}
+
+	if keys, ok := f.Tags.Lookup(TagRequiredWith); ok {
+		required, err := requiredWith(f, keys)
+		if err != nil {
+			return fmt.Errorf("checking %s tag: %w", TagRequiredWith, err)
+		}
+		if required && f.Provider == "" {
+			return fmt.Errorf("required with %s: %w", keys, &MissingKeyError{
+				Path: f.Path,
+				Key:  f.ConfigurationKey,
+			})
+		}
+	}
+
+	return checkExclusiveGroup(f)
+}
+
+// requiredIf returns true if all the comma-separated `key=value` conditions
+// hold.
+func requiredIf(f *Field, conditions string) (bool, error) {
+	for _, condition := range strings.Split(conditions, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(condition), "=")
+		if !ok {
+			return false, fmt.Errorf("invalid condition %s, expected key=value", condition)
+		}
+
+		related, err := relatedField(f, key)
+		if err != nil {
+			return false, err
+		}
+		if fieldString(related) != value {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// requiredWith returns true if any of the comma-separated keys is provided.
+func requiredWith(f *Field, keys string) (bool, error) {
+	for _, key := range strings.Split(keys, ",") {
+		related, err := relatedField(f, strings.TrimSpace(key))
+		if err != nil {
+			return false, err
+		}
+		if related.isProvided() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// relatedField returns the configurable field with the given key, relative to
+// the parent of f, or else absolute.
+func relatedField(f *Field, key string) (*Field, error) {
+	root := f
+	for root.Parent != nil {
+		root = root.Parent
+	}
+
+	prefix := strings.TrimSuffix(f.ConfigurationKey, f.Key)
+	for _, candidate := range []string{prefix + key, key} {
+		if related := findField(root, candidate); related != nil {
+			return related, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown key %s", key)
+}
+
+// findField returns the configurable field with the given key under f.
+func findField(f *Field, key string) *Field {
+	if f.Configurable && f.ConfigurationKey == key {
+		return f
+	}
+	for _, c := range f.Children {
+		if found := findField(c, key); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// fieldString formats the value of a field to be compared to the value of a
+// condition, e.g. `true` for a bool.
+func fieldString(f *Field) string {
+	v := f.Value
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.CanAddr() {
+		if secret, ok := v.Addr().Interface().(secretValue); ok {
+			v = reflect.ValueOf(secret.secretTarget()).Elem()
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// An exclusiveAlternative is a set of fields of an exclusive group to be
+// provided together.
+type exclusiveAlternative struct {
+	name   string
+	fields []*Field
+}
+
+// isProvided returns true if any of the fields of the alternative is
+// provided.
+func (a exclusiveAlternative) isProvided() bool {
+	for _, f := range a.fields {
+		if f.isProvided() {
+			return true
+		}
+	}
+	return false
+}
+
+func (a exclusiveAlternative) String() string {
+	var keys []string
+	for _, f := range a.fields {
+		keys = append(keys, f.ConfigurationKey)
+	}
+	return strings.Join(keys, "+")
+}
+
+// exclusiveGroup returns the name of the exclusive group of a field and the
+// name of its alternative, which defaults to its key.
+func exclusiveGroup(f *Field) (group, alternative string) {
+	tag, ok := f.Tags.Lookup(TagExclusiveGroup)
+	if !ok {
+		return "", ""
+	}
+
+	group, alternative, ok = strings.Cut(tag, ":")
+	if !ok {
+		alternative = f.Key
+	}
+	return group, alternative
+}
+
+// exclusiveAlternatives returns the alternatives of the exclusive group of a
+// field, sorted by the key of their first field.
+func exclusiveAlternatives(f *Field) (alternatives []exclusiveAlternative) {
+	group, _ := exclusiveGroup(f)
+	if group == "" || f.Parent == nil {
+		return nil
+	}
+
+	var siblings []*Field
+	for _, c := range f.Parent.Children {
+		if g, _ := exclusiveGroup(c); c.Configurable && g == group {
+			siblings = append(siblings, c)
+		}
+	}
+	sort.Slice(siblings, func(i, j int) bool {
+		return siblings[i].ConfigurationKey < siblings[j].ConfigurationKey
+	})
+
+	var indexes = make(map[string]int)
+	for _, c := range siblings {
+		_, name := exclusiveGroup(c)
+		i, ok := indexes[name]
+		if !ok {
+			i = len(alternatives)
+			indexes[name] = i
+			alternatives = append(alternatives, exclusiveAlternative{name: name})
+		}
+		alternatives[i].fields = append(alternatives[i].fields, c)
+	}
+
+	return alternatives
+}
+
+// checkExclusiveGroup checks the exclusive group of a field. A field missing
+// from the provided alternative is reported on its own, while the group is
+// checked once, on its field with the lowest key.
+func checkExclusiveGroup(f *Field) error {
+	alternatives := exclusiveAlternatives(f)
+	if len(alternatives) == 0 {
+		return nil
+	}
+	group, name := exclusiveGroup(f)
+
+	if f.Provider == "" {
+		for _, alternative := range alternatives {
+			if alternative.name == name && alternative.isProvided() {
+				return fmt.Errorf("exclusive group %s: required by %s: %w", group, alternative, &MissingKeyError{
+					Path: f.Path,
+					Key:  f.ConfigurationKey,
+				})
+			}
+		}
+	}
+
+	if alternatives[0].fields[0] != f {
+		return nil
+	}
+
+	var provided []string
+	var hasDefault bool
+	for _, alternative := range alternatives {
+		for _, c := range alternative.fields {
+			if _, ok := c.Tags.Lookup(TagDefault); ok {
+				hasDefault = true
+			}
+		}
+		if alternative.isProvided() {
+			provided = append(provided, alternative.String())
+		}
+	}
+
+	switch {
+	case len(provided) > 1:
+		return fmt.Errorf("exclusive group %s: %s cannot be set together", group, strings.Join(provided, " and "))
+	case len(provided) == 0 && !hasDefault:
+		return fmt.Errorf("exclusive group %s: one of %s is required", group, exclusiveGroupUsage(alternatives))
+	}
+
+	return nil
+}
+
+// exclusiveGroupUsage formats the alternatives of an exclusive group, e.g.
+// `db.dsn | db.host+db.user`.
+func exclusiveGroupUsage(alternatives []exclusiveAlternative) string {
+	var names []string
+	for _, alternative := range alternatives {
+		names = append(names, alternative.String())
+	}
+	return strings.Join(names, " | ")
+}
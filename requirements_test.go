@@ -0,0 +1,179 @@
+package zconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type requirementsTest struct {
+	TLS struct {
+		Enabled bool   `key:"enabled" default:"false"`
+		Cert    string `key:"cert" required_if:"enabled=true"`
+		Key     string `key:"key" required_if:"tls.enabled=true" required_with:"cert"`
+		CA      string `key:"ca" required_if:"enabled=true" default:"ca.pem"`
+	} `key:"tls"`
+
+	DB struct {
+		DSN  string `key:"dsn" exclusive_group:"conn"`
+		Host string `key:"host" exclusive_group:"conn:params" required_with:"user"`
+		User string `key:"user" exclusive_group:"conn:params" required_with:"host"`
+	} `key:"db"`
+
+	Auth struct {
+		Token    string `key:"token" exclusive_group:"auth"`
+		Password string `key:"password" exclusive_group:"auth" default:""`
+	} `key:"auth"`
+}
+
+func TestCheckRequirements(t *testing.T) {
+	for name, c := range map[string]struct {
+		values   map[string]string
+		expected string
+	}{
+		"dsn": {
+			values: map[string]string{"db.dsn": "postgres://"},
+		},
+		"params": {
+			values: map[string]string{"db.host": "localhost", "db.user": "app"},
+		},
+		"tls": {
+			values: map[string]string{"db.dsn": "postgres://", "tls.enabled": "true", "tls.cert": "cert.pem", "tls.key": "key.pem"},
+		},
+		"required if": {
+			values:   map[string]string{"db.dsn": "postgres://", "tls.enabled": "true", "tls.key": "key.pem"},
+			expected: "executing hook on field $.TLS.Cert: required if enabled=true: missing key tls.cert",
+		},
+		"absolute required if": {
+			values:   map[string]string{"db.dsn": "postgres://", "tls.enabled": "true", "tls.cert": "cert.pem"},
+			expected: "executing hook on field $.TLS.Key: required if tls.enabled=true: missing key tls.key",
+		},
+		"required with": {
+			values:   map[string]string{"db.host": "localhost"},
+			expected: "executing hook on field $.DB.User: required with host: missing key db.user",
+		},
+		"exclusive": {
+			values:   map[string]string{"db.dsn": "postgres://", "db.host": "localhost", "db.user": "app"},
+			expected: "executing hook on field $.DB.DSN: exclusive group conn: db.dsn and db.host+db.user cannot be set together",
+		},
+		"exclusive with default": {
+			values:   map[string]string{"db.dsn": "postgres://", "auth.token": "abc", "auth.password": "hunter2"},
+			expected: "executing hook on field $.Auth.Password: exclusive group auth: auth.password and auth.token cannot be set together",
+		},
+		"exclusive missing": {
+			values:   map[string]string{},
+			expected: "executing hook on field $.DB.DSN: exclusive group conn: one of db.dsn | db.host+db.user is required",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var repository Repository
+			repository.AddProviders(TestProvider{name: "test", values: c.values})
+			repository.AddParsers(ParseString)
+
+			var s requirementsTest
+			err := NewProcessor(repository.Hook, CheckRequirements).Process(context.Background(), &s)
+			if c.expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if s.TLS.CA != "ca.pem" {
+					t.Errorf("expected the default value, got %q", s.TLS.CA)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != c.expected {
+				t.Fatalf("expected error %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckRequirementsErrors(t *testing.T) {
+	var repository Repository
+	repository.AddProviders(TestProvider{name: "test", values: map[string]string{"enabled": "true"}})
+	repository.AddParsers(ParseString)
+
+	t.Run("missing key", func(t *testing.T) {
+		var s struct {
+			Enabled bool   `key:"enabled"`
+			Cert    string `key:"cert" required_if:"enabled=true"`
+		}
+
+		err := NewProcessor(repository.Hook, CheckRequirements).Process(context.Background(), &s)
+
+		var missing *MissingKeyError
+		if !errors.As(err, &missing) || missing.Key != "cert" {
+			t.Fatalf("expected a MissingKeyError, got %v", err)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		var s struct {
+			Cert string `key:"cert" required_with:"tls.enabled"`
+		}
+
+		err := NewProcessor(repository.Hook, CheckRequirements).Process(context.Background(), &s)
+		if err == nil || !strings.Contains(err.Error(), "unknown key tls.enabled") {
+			t.Fatalf("expected an unknown key error, got %v", err)
+		}
+	})
+
+	t.Run("incomplete alternative", func(t *testing.T) {
+		type db struct {
+			DSN  string `key:"dsn" exclusive_group:"conn"`
+			Host string `key:"host" exclusive_group:"conn:hostuser"`
+			User string `key:"user" exclusive_group:"conn:hostuser"`
+			Port int    `key:"port" exclusive_group:"conn:hostuser" default:"5432"`
+		}
+
+		var repository Repository
+		repository.AddProviders(TestProvider{name: "test", values: map[string]string{"db.host": "localhost"}})
+		repository.AddParsers(ParseString)
+
+		var s struct {
+			DB db `key:"db"`
+		}
+
+		err := NewProcessor(repository.Hook, CheckRequirements).Process(context.Background(), &s)
+		expected := "executing hook on field $.DB.User: exclusive group conn: required by db.host+db.port+db.user: missing key db.user"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected error %q, got %v", expected, err)
+		}
+
+		var missing *MissingKeyError
+		if !errors.As(err, &missing) || missing.Key != "db.user" {
+			t.Fatalf("expected a MissingKeyError, got %v", err)
+		}
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		var s struct {
+			Enabled bool   `key:"enabled"`
+			Cert    string `key:"cert" required_if:"enabled"`
+		}
+
+		err := NewProcessor(repository.Hook, CheckRequirements).Process(context.Background(), &s)
+		if err == nil || !strings.Contains(err.Error(), "invalid condition enabled") {
+			t.Fatalf("expected an invalid condition error, got %v", err)
+		}
+	})
+}
+
+func TestRequirementsUsage(t *testing.T) {
+	var s requirementsTest
+
+	var output bytes.Buffer
+	FprintUsageVal(&output, "markdown", usageFields(t, &s))
+
+	for _, expected := range []string{
+		"| `tls.cert` | `--tls.cert` | `TLS_CERT` | `string` |  | no | (required if: enabled=true) |",
+		"| `db.host` | `--db.host` | `DB_HOST` | `string` |  | no | (required with: user) (exclusive group conn: db.dsn \\| db.host+db.user) |",
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Errorf("expected usage to contain %q, got:\n%s", expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package zconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// A Secret holds a sensitive value, e.g. a password, that is configured like
+// a value of type T but redacted whenever it is formatted, marshaled to JSON
+// or logged. The fields of type Secret are secret fields, without the need of
+// a `secret` tag.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret returns a Secret holding the given value.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Value returns the value of the secret.
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+// String returns the redacted value.
+func (s Secret[T]) String() string {
+	return Redacted
+}
+
+// GoString returns the redacted value.
+func (s Secret[T]) GoString() string {
+	return Redacted
+}
+
+// Format writes the redacted value whatever the verb, e.g. %d or %+v which
+// would otherwise print the value.
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		fmt.Fprintf(f, "%q", Redacted)
+		return
+	}
+	fmt.Fprint(f, Redacted)
+}
+
+// MarshalJSON returns the redacted value as a JSON string.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// secretValue is implemented by the pointers to a Secret, to configure the
+// value they hold.
+type secretValue interface {
+	secretTarget() interface{}
+}
+
+func (s *Secret[T]) secretTarget() interface{} {
+	return &s.value
+}
+
+// Used for type comparison.
+var typeSecretValue = reflect.TypeOf((*secretValue)(nil)).Elem()
+
+// isSecretType returns true if the type is a Secret or a pointer to a Secret.
+func isSecretType(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return reflect.PtrTo(typ).Implements(typeSecretValue)
+}
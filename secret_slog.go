@@ -0,0 +1,12 @@
+//go:build go1.21
+
+package zconfig
+
+import (
+	"log/slog"
+)
+
+// LogValue returns the redacted value, for the log/slog package.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
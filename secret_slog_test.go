@@ -0,0 +1,21 @@
+//go:build go1.21
+
+package zconfig
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSecretLogValue(t *testing.T) {
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, nil))
+
+	logger.Info("configured", "password", NewSecret("hunter2"))
+
+	if strings.Contains(output.String(), "hunter2") || !strings.Contains(output.String(), "password=******") {
+		t.Errorf("expected the secret to be redacted, got %s", output.String())
+	}
+}
@@ -0,0 +1,166 @@
+package zconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	secret := NewSecret(1234)
+	if secret.Value() != 1234 {
+		t.Errorf("unexpected value: %d", secret.Value())
+	}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%d", "%x"} {
+		if formatted := fmt.Sprintf(format, secret); formatted != Redacted {
+			t.Errorf("%s: expected %s, got %s", format, Redacted, formatted)
+		}
+	}
+	if formatted := fmt.Sprintf("%q", secret); formatted != `"******"` {
+		t.Errorf("%%q: unexpected %s", formatted)
+	}
+
+	s := struct {
+		Password Secret[string]
+		Pointer  *Secret[string]
+	}{NewSecret("hunter2"), &Secret[string]{"hunter2"}}
+	if formatted := fmt.Sprintf("%+v", s); strings.Contains(formatted, "hunter2") {
+		t.Errorf("expected the secret to be redacted, got %s", formatted)
+	}
+
+	raw, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(raw) != `{"Password":"******","Pointer":"******"}` {
+		t.Errorf("unexpected json: %s", raw)
+	}
+}
+
+func TestSecretConfigure(t *testing.T) {
+	var repository Repository
+	repository.AddProviders(TestProvider{name: "test", values: map[string]string{
+		"password": "hunter2",
+		"pin":      "1234",
+		"tokens":   "a,b",
+	}})
+	repository.AddParsers(ParseString)
+
+	var s struct {
+		Password Secret[string]   `key:"password"`
+		Pin      *Secret[int]     `key:"pin"`
+		Tokens   Secret[[]string] `key:"tokens"`
+		Timeout  Secret[float64]  `key:"timeout" default:"1.5"`
+		Plain    string           `key:"plain" default:"foo"`
+		Tagged   []int            `key:"tagged" secret:"true" default:"1,2"`
+	}
+
+	root, err := walk(reflect.ValueOf(&s), reflect.StructField{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, f := range root.Children {
+		if !f.IsLeaf() {
+			t.Errorf("expected field %s to be a leaf", f.Path)
+		}
+		if f.IsSecret() != (f.Path != "$.Plain") {
+			t.Errorf("field %s: unexpected secret %v", f.Path, f.IsSecret())
+		}
+	}
+
+	err = NewProcessor(repository.Hook).Process(context.Background(), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Password.Value() != "hunter2" || s.Pin.Value() != 1234 || s.Timeout.Value() != 1.5 {
+		t.Errorf("unexpected configuration: %s %d %f", s.Password.Value(), s.Pin.Value(), s.Timeout.Value())
+	}
+	if !reflect.DeepEqual(s.Tokens.Value(), []string{"a", "b"}) {
+		t.Errorf("unexpected tokens: %v", s.Tokens.Value())
+	}
+}
+
+func TestSecretParseError(t *testing.T) {
+	var repository Repository
+	repository.AddProviders(TestProvider{name: "test", values: map[string]string{
+		"pin":  "hunter2",
+		"port": "http",
+	}})
+	repository.AddParsers(ParseString)
+
+	var s struct {
+		Pin  Secret[int] `key:"pin"`
+		Port int         `key:"port"`
+	}
+
+	processor := NewProcessor(repository.Hook)
+	processor.CollectErrors = true
+	err := processor.Process(context.Background(), &s)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("expected the secret to be redacted, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"http"`) {
+		t.Errorf("expected the raw value of the plain field, got %v", err)
+	}
+}
+
+func TestSecretParseErrorList(t *testing.T) {
+	provider, err := NewYAMLProviderFromReader(strings.NewReader("pins: [1, hunter2]\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var repository Repository
+	repository.AddProviders(provider)
+	repository.AddParsers(ParseString, ParseNative)
+
+	var s struct {
+		Pins []int `key:"pins" secret:"true"`
+	}
+
+	err = NewProcessor(repository.Hook).Process(context.Background(), &s)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+
+	if !strings.HasSuffix(err.Error(), "parsing value for key pins: invalid value for type []int") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("expected the secret to be left out, got %v", err)
+	}
+	if parseErr.Err == nil || errors.Unwrap(parseErr) != parseErr.Err {
+		t.Errorf("expected the parser error to be wrapped, got %v", errors.Unwrap(parseErr))
+	}
+}
+
+func TestSecretUsage(t *testing.T) {
+	var s struct {
+		Password string         `key:"password" secret:"true" default:"hunter2"`
+		Token    Secret[string] `key:"token" default:"hunter2"`
+	}
+
+	for _, val := range []string{"", "markdown", "json", "man"} {
+		var output bytes.Buffer
+		FprintUsageVal(&output, val, usageFields(t, &s))
+
+		if strings.Contains(output.String(), "hunter2") {
+			t.Errorf("%q: expected the secret defaults to be redacted, got:\n%s", val, output.String())
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package zconfig
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// The key of the prototype element of a slice of structs, e.g. in the
+// `backends.N.addr` key displayed by the usage message.
+const prototypeIndex = "N"
+
+var typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isStructSlice returns true if the field is a slice of structs or pointers to
+// structs with a key, configured element by element through indexed keys,
+// e.g. `backends.0.addr`, when they are found. The structs parsed from a text,
+// like the secrets, are not.
+func (f *Field) isStructSlice() bool {
+	if f.Key == "" || f.Value.Kind() != reflect.Slice {
+		return false
+	}
+
+	typ := f.Value.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Struct && !isSecretType(typ) && !reflect.PtrTo(typ).Implements(typeTextUnmarshaler)
+}
+
+// newElement returns a new element of a slice of structs to be walked: a
+// pointer to a struct, so its methods with a pointer receiver (e.g. Init) are
+// found, or a nil pointer to be allocated.
+func newElement(typ reflect.Type) reflect.Value {
+	if typ.Kind() == reflect.Ptr {
+		return reflect.New(typ).Elem()
+	}
+	return reflect.New(typ)
+}
+
+// walkElement walks an element of a slice of structs, keyed by its index.
+func walkElement(slice *Field, v reflect.Value, index string) (*Field, error) {
+	return walk(v, reflect.StructField{
+		Name: index,
+		Tag:  reflect.StructTag(fmt.Sprintf(`%s:"%s"`, TagKey, index)),
+	}, slice)
+}
+
+// expandSlices replaces the prototype element of the slices of structs under f
+// by their actual elements. The elements are counted by looking up their keys
+// index by index, until none of the keys of an element has a value, so the
+// indexes must start at 0 and be contiguous. A slice without element is
+// configured as a whole instead, like the other leaves, from its own key or
+// its default value. As for mark, the key is the one of the parent of f.
+func expandSlices(f *Field, key string, exists func(key string) (bool, error)) error {
+	var parent = key
+	if f.Key != "" {
+		key = key + "." + f.Key
+	}
+
+	if !f.isStructSlice() {
+		for _, c := range f.Children {
+			err := expandSlices(c, key, exists)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var count int
+	for ; ; count++ {
+		element, err := expandElement(f, newElement(f.Value.Type().Elem()), count, key, exists)
+		if err != nil {
+			return err
+		}
+
+		found, err := elementExists(element, exists)
+		if err != nil {
+			return fmt.Errorf("looking up element %d of %s: %w", count, f.Path, err)
+		}
+		if !found {
+			break
+		}
+	}
+
+	if count == 0 {
+		markSlice(f, key, parent)
+		return nil
+	}
+
+	f.ConfigurationKey = key[1:]
+	f.Children = nil
+
+	slice := reflect.MakeSlice(f.Value.Type(), count, count)
+	f.Value.Set(slice)
+
+	for i := 0; i < count; i++ {
+		v := slice.Index(i)
+		if v.Kind() != reflect.Ptr {
+			v = v.Addr()
+		}
+
+		element, err := expandElement(f, v, i, key, exists)
+		if err != nil {
+			return err
+		}
+		f.Children = append(f.Children, element)
+	}
+
+	return nil
+}
+
+// expandElement walks and marks the element of a slice of structs at the
+// given index, and expands its own slices. The key is the one of the slice.
+func expandElement(slice *Field, v reflect.Value, index int, key string, exists func(key string) (bool, error)) (*Field, error) {
+	element, err := walkElement(slice, v, strconv.Itoa(index))
+	if err != nil {
+		return nil, fmt.Errorf("walking element %d of %s: %w", index, slice.Path, err)
+	}
+
+	mark(element, key)
+
+	err = expandSlices(element, key, exists)
+	if err != nil {
+		return nil, err
+	}
+
+	return element, nil
+}
+
+// elementExists returns true if one of the keys of the configurable fields
+// under an element, aliases included, has a value.
+func elementExists(f *Field, exists func(key string) (bool, error)) (bool, error) {
+	if f.Configurable {
+		keys := append([]string{f.ConfigurationKey}, f.AliasKeys...)
+		for _, key := range append(keys, f.DeprecatedKeys...) {
+			found, err := exists(key)
+			if err != nil || found {
+				return found, err
+			}
+		}
+	}
+
+	for _, c := range f.Children {
+		found, err := elementExists(c, exists)
+		if err != nil || found {
+			return found, err
+		}
+	}
+
+	return false, nil
+}
+
+// leafSlices configures the slices of structs under f as a whole, like the
+// other leaves, for the processors without an Exists function to count their
+// elements. As for mark, the key is the one of the parent of f.
+func leafSlices(f *Field, key string) {
+	if f.Key == "" && !f.Anonymous {
+		return
+	}
+
+	var parent = key
+	if f.Key != "" {
+		key = key + "." + f.Key
+	}
+
+	if !f.isStructSlice() {
+		for _, c := range f.Children {
+			leafSlices(c, key)
+		}
+		return
+	}
+
+	markSlice(f, key, parent)
+}
+
+// markSlice marks a slice of structs as configurable as a whole, like the other
+// leaves, given its key and the one of its parent.
+func markSlice(f *Field, key, parent string) {
+	f.Children = nil
+	f.Configurable = true
+	f.ConfigurationKey = key[1:]
+	f.AliasKeys = aliasKeys(f, TagAlias, parent)
+	f.DeprecatedKeys = aliasKeys(f, TagDeprecated, parent)
+}
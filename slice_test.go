@@ -0,0 +1,279 @@
+package zconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type sliceBackend struct {
+	Addr        string `key:"addr"`
+	Weight      int    `key:"weight" default:"1"`
+	Initialized bool
+}
+
+func (b *sliceBackend) Init(ctx context.Context) error {
+	b.Initialized = true
+	return nil
+}
+
+type sliceService struct {
+	Backends []sliceBackend `key:"backends"`
+	Mirrors  []*struct {
+		Name  string `key:"name"`
+		Paths []struct {
+			Prefix string `key:"prefix"`
+		} `key:"paths" optional:"true"`
+	} `key:"mirrors" optional:"true"`
+}
+
+func processSlices(t *testing.T, s interface{}, providers ...Provider) error {
+	t.Helper()
+
+	var repository Repository
+	repository.AddProviders(providers...)
+	repository.AddParsers(ParseString, ParseNative)
+
+	processor := NewProcessor(repository.Hook, CheckRequirements, Validate, Initialize)
+	processor.Exists = repository.Exists
+
+	return processor.Process(context.Background(), s)
+}
+
+func TestSlices(t *testing.T) {
+	t.Run("indexed keys", func(t *testing.T) {
+		var s sliceService
+		err := processSlices(t, &s, TestProvider{name: "test", values: map[string]string{
+			"backends.0.addr":          "10.0.0.1:80",
+			"backends.1.addr":          "10.0.0.2:80",
+			"backends.1.weight":        "3",
+			"backends.3.addr":          "10.0.0.4:80",
+			"mirrors.0.name":           "eu",
+			"mirrors.0.paths.0.prefix": "/static",
+			"mirrors.0.paths.1.prefix": "/media",
+			"mirrors.1.name":           "us",
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []sliceBackend{
+			{Addr: "10.0.0.1:80", Weight: 1, Initialized: true},
+			{Addr: "10.0.0.2:80", Weight: 3, Initialized: true},
+		}
+		if len(s.Backends) != len(expected) {
+			t.Fatalf("expected the indexes following a gap to be ignored, got %+v", s.Backends)
+		}
+		for i := range expected {
+			if s.Backends[i] != expected[i] {
+				t.Errorf("backend %d: expected %+v, got %+v", i, expected[i], s.Backends[i])
+			}
+		}
+
+		if len(s.Mirrors) != 2 || s.Mirrors[0].Name != "eu" || s.Mirrors[1].Name != "us" {
+			t.Fatalf("unexpected mirrors: %+v", s.Mirrors)
+		}
+		if len(s.Mirrors[0].Paths) != 2 || s.Mirrors[0].Paths[1].Prefix != "/media" || s.Mirrors[1].Paths != nil {
+			t.Errorf("unexpected paths: %+v %+v", s.Mirrors[0].Paths, s.Mirrors[1].Paths)
+		}
+	})
+
+	t.Run("env and flags", func(t *testing.T) {
+		t.Setenv("BACKENDS_0_ADDR", "10.0.0.1:80")
+		t.Setenv("BACKENDS_1_ADDR", "10.0.0.2:80")
+
+		var s sliceService
+		err := processSlices(t, &s, NewEnvProvider(), &ArgsProvider{Args: map[string]string{
+			"backends.1.weight": "5",
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(s.Backends) != 2 || s.Backends[0].Addr != "10.0.0.1:80" || s.Backends[1].Weight != 5 {
+			t.Errorf("unexpected backends: %+v", s.Backends)
+		}
+	})
+
+	t.Run("native list", func(t *testing.T) {
+		provider, err := NewYAMLProviderFromReader(strings.NewReader(`
+backends:
+  - addr: 10.0.0.1:80
+  - addr: 10.0.0.2:80
+    weight: 2
+mirrors:
+  - name: eu
+    paths:
+      - prefix: /static
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var s sliceService
+		err = processSlices(t, &s, provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(s.Backends) != 2 || s.Backends[1].Addr != "10.0.0.2:80" || s.Backends[1].Weight != 2 || !s.Backends[1].Initialized {
+			t.Errorf("unexpected backends: %+v", s.Backends)
+		}
+		if len(s.Mirrors) != 1 || len(s.Mirrors[0].Paths) != 1 || s.Mirrors[0].Paths[0].Prefix != "/static" {
+			t.Errorf("unexpected mirrors: %+v", s.Mirrors)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		var s sliceService
+		err := processSlices(t, &s, TestProvider{name: "test", values: map[string]string{}})
+
+		var missing *MissingKeyError
+		if !errors.As(err, &missing) || missing.Key != "backends" {
+			t.Fatalf("expected a missing key error for backends, got %v", err)
+		}
+		if s.Mirrors != nil {
+			t.Errorf("expected the optional mirrors to be left unset, got %+v", s.Mirrors)
+		}
+	})
+
+	t.Run("missing element key", func(t *testing.T) {
+		var s sliceService
+		err := processSlices(t, &s, TestProvider{name: "test", values: map[string]string{
+			"backends.0.weight": "2",
+		}})
+
+		var missing *MissingKeyError
+		if !errors.As(err, &missing) || missing.Key != "backends.0.addr" {
+			t.Fatalf("expected a missing key error for backends.0.addr, got %v", err)
+		}
+	})
+
+	t.Run("set beforehand", func(t *testing.T) {
+		s := sliceService{Backends: []sliceBackend{{Addr: "localhost:80"}, {Addr: "localhost:81"}}}
+		err := processSlices(t, &s, TestProvider{name: "test", values: map[string]string{
+			"backends.0.addr": "10.0.0.1:80",
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(s.Backends) != 1 || s.Backends[0].Addr != "10.0.0.1:80" {
+			t.Errorf("expected the backends to be replaced, got %+v", s.Backends)
+		}
+	})
+
+	t.Run("whole value", func(t *testing.T) {
+		type pair struct {
+			A string
+		}
+
+		// Without indexed keys, the slice is parsed as a whole like any
+		// other leaf, from its key or its default value.
+		parser := func(raw, res interface{}) error {
+			pairs, ok := res.(*[]pair)
+			if !ok {
+				return ErrNotParseable
+			}
+			return json.Unmarshal([]byte(raw.(string)), pairs)
+		}
+
+		for name, c := range map[string]struct {
+			values   map[string]string
+			expected string
+		}{
+			"key":     {map[string]string{"pairs": `[{"A":"x"}]`}, "x"},
+			"default": {map[string]string{}, "y"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				var repository Repository
+				repository.AddProviders(TestProvider{name: "test", values: c.values})
+				repository.AddParsers(parser)
+
+				processor := NewProcessor(repository.Hook)
+				processor.Exists = repository.Exists
+
+				var s struct {
+					Pairs []pair `key:"pairs" default:"[{\"A\":\"y\"}]"`
+				}
+				err := processor.Process(context.Background(), &s)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if len(s.Pairs) != 1 || s.Pairs[0].A != c.expected {
+					t.Errorf("expected the pairs to be parsed as a whole, got %+v", s.Pairs)
+				}
+			})
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		type node struct {
+			Name     string `key:"name"`
+			Children []node `key:"children"`
+		}
+
+		var s node
+		err := processSlices(t, &s, TestProvider{name: "test", values: map[string]string{"name": "root"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.Name != "root" {
+			t.Errorf("unexpected name %s", s.Name)
+		}
+	})
+
+	t.Run("without exists", func(t *testing.T) {
+		parser := func(raw, res interface{}) error {
+			backends, ok := res.(*[]sliceBackend)
+			if !ok {
+				return ParseString(raw, res)
+			}
+			for _, addr := range strings.Split(raw.(string), ",") {
+				*backends = append(*backends, sliceBackend{Addr: addr})
+			}
+			return nil
+		}
+
+		var repository Repository
+		repository.AddProviders(TestProvider{name: "test", values: map[string]string{"backends": "10.0.0.1:80,10.0.0.2:80"}})
+		repository.AddParsers(parser)
+
+		var s sliceService
+		err := NewProcessor(repository.Hook).Process(context.Background(), &s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(s.Backends) != 2 || s.Backends[1].Addr != "10.0.0.2:80" {
+			t.Errorf("expected the backends to be parsed as a whole, got %+v", s.Backends)
+		}
+	})
+}
+
+func TestSlices_Usage(t *testing.T) {
+	var keys []string
+	for _, f := range usageFields(t, new(sliceService)) {
+		if f.Configurable {
+			keys = append(keys, f.ConfigurationKey+"="+f.Path)
+		}
+	}
+
+	expected := map[string]bool{
+		"backends.N.addr=$.Backends[N].Addr":                    true,
+		"backends.N.weight=$.Backends[N].Weight":                true,
+		"mirrors.N.name=$.Mirrors[N].Name":                      true,
+		"mirrors.N.paths.N.prefix=$.Mirrors[N].Paths[N].Prefix": true,
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("expected the keys of the prototype elements, got %v", keys)
+	}
+	for _, key := range keys {
+		if !expected[key] {
+			t.Errorf("unexpected key %s", key)
+		}
+	}
+}
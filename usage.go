@@ -0,0 +1,364 @@
+package zconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hchargois/flexwriter"
+)
+
+// DefaultUsageVal prints a usage message that lists the fields with their keys
+// in CLI form (e.g. --foo) and environment variable form (e.g. FOO), as well as
+// the fields descriptions and default values (if any).
+//
+// If called with the "cli" value, only the CLI form is printed, and if called
+// with the "env" value, only the environment variable form is printed. The
+// "markdown", "json" and "man" values print a reference of the fields in the
+// corresponding format, with their types and whether they are required, e.g.
+// to generate documentation. The "values" value prints the value each field
+// would be configured with and the provider supplying it, as retrieved from the
+// DefaultRepository (or by the Lookup function of the processor when called by
+// Process), with the values of the secret fields masked. Any other value
+// (including an empty value) prints both forms.
+//
+// The fields with a group, as given by Field.Group, are listed apart under the
+// name of their group.
+func DefaultUsageVal(val string, fields []*Field) {
+	FprintUsageVal(os.Stdout, val, fields)
+}
+
+// FprintUsageVal writes the usage message of DefaultUsageVal to w.
+func FprintUsageVal(w io.Writer, val string, fields []*Field) {
+	fprintUsageVal(w, val, fields, DefaultRepository.LookupField)
+}
+
+// fprintUsageVal writes the usage message of DefaultUsageVal to w, with the
+// values retrieved by lookup, if any.
+func fprintUsageVal(w io.Writer, val string, fields []*Field, lookup func(f *Field) (interface{}, string, bool, error)) {
+	options := usageOptions(fields)
+
+	switch val {
+	case "values":
+		for i := range options {
+			options[i].value, options[i].provider = usageValue(options[i].field, lookup)
+		}
+		fprintTableUsage(w, val, options)
+	case "markdown":
+		fprintMarkdownUsage(w, options)
+	case "json":
+		fprintJSONUsage(w, options)
+	case "man":
+		fprintManUsage(w, options)
+	default:
+		fprintTableUsage(w, val, options)
+	}
+}
+
+// A usageOption describes a configurable field in the usage message.
+type usageOption struct {
+	Key         string   `json:"key"`
+	Flag        string   `json:"flag"`
+	Env         string   `json:"env"`
+	Description string   `json:"description,omitempty"`
+	Default     *string  `json:"default,omitempty"`
+	Required    bool     `json:"required"`
+	Type        string   `json:"type"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Deprecated  []string `json:"deprecated,omitempty"`
+	Group       string   `json:"group,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+
+	RequiredIf     string `json:"required_if,omitempty"`
+	RequiredWith   string `json:"required_with,omitempty"`
+	ExclusiveGroup string `json:"exclusive_group,omitempty"`
+
+	field                 *Field
+	value                 string
+	provider              string
+	exclusiveAlternatives string
+}
+
+// usageOptions returns the options of the configurable fields, sorted by key.
+func usageOptions(fields []*Field) (options []usageOption) {
+	for _, f := range fields {
+		if !f.Configurable {
+			continue
+		}
+
+		option := usageOption{
+			Key:        f.ConfigurationKey,
+			Flag:       Args.FormatFieldKey(f),
+			Env:        Env.FormatFieldKey(f),
+			Type:       f.Value.Type().String(),
+			Aliases:    f.AliasKeys,
+			Deprecated: f.DeprecatedKeys,
+			Group:      f.Group(),
+			Secret:     f.IsSecret(),
+			field:      f,
+		}
+		option.Description, _ = f.Tags.Lookup(TagDescription)
+		if def, ok := f.Tags.Lookup(TagDefault); ok {
+			if option.Secret {
+				def = Redacted
+			}
+			option.Default = &def
+		}
+		option.Required = option.Default == nil && !f.isConditional() && !f.isOptional() && !f.hasOptionalAncestor()
+		option.RequiredIf, _ = f.Tags.Lookup(TagRequiredIf)
+		option.RequiredWith, _ = f.Tags.Lookup(TagRequiredWith)
+		if alternatives := exclusiveAlternatives(f); len(alternatives) != 0 {
+			option.ExclusiveGroup, _ = exclusiveGroup(f)
+			option.exclusiveAlternatives = exclusiveGroupUsage(alternatives)
+		}
+
+		options = append(options, option)
+	}
+
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Key < options[j].Key
+	})
+
+	return options
+}
+
+// fprintTableUsage writes the options in columns. The options outside any
+// group are split between the required and optional ones, then each group is
+// listed with its required options first.
+func fprintTableUsage(w io.Writer, val string, options []usageOption) {
+	columns := []flexwriter.Column{
+		flexwriter.Rigid{},      // CLI option name
+		flexwriter.Rigid{},      // env variable name
+		flexwriter.Shrinkable{}, // description or value
+		flexwriter.Rigid{},      // default value or provider
+	}
+	switch val {
+	case "env":
+		columns[0] = flexwriter.Omit{}
+	case "cli":
+		columns[1] = flexwriter.Omit{}
+	}
+	newWriter := func() *flexwriter.Writer {
+		writer := flexwriter.New()
+		writer.SetOutput(w)
+		writer.SetColumns(columns...)
+		return writer
+	}
+
+	var groups []string
+	var grouped = make(map[string][]usageOption)
+	for _, option := range options {
+		if _, ok := grouped[option.Group]; !ok && option.Group != "" {
+			groups = append(groups, option.Group)
+		}
+		grouped[option.Group] = append(grouped[option.Group], option)
+	}
+
+	if len(grouped[""]) != 0 || len(groups) == 0 {
+		required := newWriter()
+		optional := newWriter()
+		for _, option := range grouped[""] {
+			if option.Required {
+				required.WriteRow(usageRow(val, option)...)
+			} else {
+				optional.WriteRow(usageRow(val, option)...)
+			}
+		}
+
+		fmt.Fprintf(w, "\nRequired parameters:\n")
+		_ = required.Flush()
+
+		fmt.Fprintf(w, "\nOptional parameters:\n")
+		_ = optional.Flush()
+	}
+
+	for _, group := range groups {
+		writer := newWriter()
+		for _, required := range []bool{true, false} {
+			for _, option := range grouped[group] {
+				if option.Required == required {
+					writer.WriteRow(usageRow(val, option)...)
+				}
+			}
+		}
+
+		fmt.Fprintf(w, "\n%s:\n", group)
+		_ = writer.Flush()
+	}
+}
+
+// usageRow returns the columns of an option in the usage message.
+func usageRow(val string, option usageOption) []any {
+	if val == "values" {
+		return []any{"--" + option.Flag, option.Env, option.value, "(" + option.provider + ")"}
+	}
+
+	desc := usageDescription(val, option)
+
+	row := []any{"--" + option.Flag, option.Env, desc}
+	switch {
+	case option.Default != nil:
+		row = append(row, "("+*option.Default+")")
+	case option.Group != "" && option.Required:
+		// The grouped options aren't listed under a "Required parameters"
+		// heading.
+		row = append(row, "(required)")
+	}
+	return row
+}
+
+// usageValue returns the value a field would be configured with, and the name
+// of the provider supplying it, as retrieved by lookup. Without lookup, the
+// provider is unknown.
+func usageValue(f *Field, lookup func(f *Field) (interface{}, string, bool, error)) (value, provider string) {
+	if lookup == nil {
+		return "", "unknown"
+	}
+
+	raw, provider, found, err := lookup(f)
+	switch {
+	case err != nil:
+		return "error: " + err.Error(), provider
+	case !found:
+		return "", "missing"
+	case f.IsSecret():
+		return Redacted, provider
+	default:
+		return fmt.Sprint(raw), provider
+	}
+}
+
+// fprintMarkdownUsage writes the options as a Markdown table.
+func fprintMarkdownUsage(w io.Writer, options []usageOption) {
+	escape := strings.NewReplacer("|", "\\|", "\n", " ").Replace
+	code := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "`" + escape(s) + "`"
+	}
+
+	fmt.Fprintln(w, "| Key | Flag | Environment variable | Type | Default | Required | Description |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- | --- | --- |")
+	for _, option := range options {
+		var def, required = "", "no"
+		if option.Default != nil {
+			def = code(*option.Default)
+		}
+		if option.Required {
+			required = "yes"
+		}
+
+		desc := usageDescription("", option)
+
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			code(option.Key), code("--"+option.Flag), code(option.Env),
+			code(option.Type), def, required, escape(desc))
+	}
+}
+
+// fprintJSONUsage writes the options as a JSON array.
+func fprintJSONUsage(w io.Writer, options []usageOption) {
+	if options == nil {
+		options = []usageOption{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(options)
+}
+
+// fprintManUsage writes the options as a man page in the roff format, named
+// after the program.
+func fprintManUsage(w io.Writer, options []usageOption) {
+	name := programName()
+
+	fmt.Fprintf(w, ".TH %s 1\n", manEscape(strings.ToUpper(name)))
+	fmt.Fprintf(w, ".SH NAME\n%s\n", manEscape(name))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B %s\n[\\fIOPTIONS\\fR]\n", manEscape(name))
+	fmt.Fprintf(w, ".SH OPTIONS\n")
+	for _, option := range options {
+		fmt.Fprintf(w, ".TP\n\\fB%s\\fR, \\fB%s\\fR\n", manEscape("--"+option.Flag), manEscape(option.Env))
+
+		desc := usageDescription("", option)
+		if desc != "" {
+			fmt.Fprintf(w, "%s\n.br\n", manEscape(desc))
+		}
+
+		details := "Type: " + option.Type
+		if option.Default != nil {
+			details += ", default: " + *option.Default
+		}
+		if option.Required {
+			details += ", required"
+		}
+		fmt.Fprintf(w, "%s.\n", manEscape(details))
+	}
+}
+
+// manEscape escapes the backslashes and dashes of a text for the roff format,
+// and makes sure it is not taken as a request if it starts with a dot or a
+// quote.
+func manEscape(s string) string {
+	s = strings.NewReplacer("\\", "\\e", "-", "\\-", "\n", " ").Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = "\\&" + s
+	}
+	return s
+}
+
+// usageDescription returns the description of an option, followed by its
+// alternative keys and its conditional requirements.
+func usageDescription(val string, option usageOption) string {
+	desc := option.Description
+	for _, extra := range []string{
+		aliasesUsage(val, "alias", option.Aliases),
+		aliasesUsage(val, "deprecated", option.Deprecated),
+		conditionUsage("required if", option.RequiredIf),
+		conditionUsage("required with", option.RequiredWith),
+		conditionUsage("exclusive group "+option.ExclusiveGroup, option.exclusiveAlternatives),
+	} {
+		desc = strings.TrimSpace(desc + " " + extra)
+	}
+	return desc
+}
+
+// conditionUsage formats a conditional requirement of a field for the usage
+// message.
+func conditionUsage(label, condition string) string {
+	if condition == "" {
+		return ""
+	}
+	return fmt.Sprintf("(%s: %s)", label, condition)
+}
+
+// aliasesUsage formats the alternative keys of a field for the usage message,
+// in the forms selected by the value of the --help flag.
+func aliasesUsage(val, label string, keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+
+	var names []string
+	for _, key := range keys {
+		if val != "env" {
+			names = append(names, "--"+key)
+		}
+		if val != "cli" {
+			names = append(names, Env.FormatKey(key))
+		}
+	}
+
+	return fmt.Sprintf("(%s: %s)", label, strings.Join(names, ", "))
+}
+
+// DefaultUsage prints a usage message as DefaultUsageVal would with an empty
+// value.
+//
+// Deprecated: use DefaultUsageVal.
+func DefaultUsage(fields []*Field) {
+	DefaultUsageVal("", fields)
+}
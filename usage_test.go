@@ -0,0 +1,292 @@
+package zconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProcessorHelp(t *testing.T) {
+	oldArgs := Args
+	t.Cleanup(func() {
+		Args = oldArgs
+	})
+	Args = &ArgsProvider{Args: map[string]string{"help": "cli"}}
+
+	var s struct {
+		Addr    string `key:"addr" description:"listen address"`
+		Workers int    `key:"workers" default:"4"`
+	}
+
+	t.Run("output", func(t *testing.T) {
+		var hooked bool
+		var output bytes.Buffer
+
+		p := NewProcessor(func(context.Context, *Field) error {
+			hooked = true
+			return nil
+		})
+		p.Output = &output
+		p.ReturnOnHelp = true
+
+		err := p.Process(context.Background(), &s)
+		if !errors.Is(err, ErrHelpRequested) {
+			t.Fatalf("expected ErrHelpRequested, got %v", err)
+		}
+
+		if hooked {
+			t.Error("expected the hooks not to run when help is requested")
+		}
+
+		usage := output.String()
+		for _, expected := range []string{"Required parameters:", "--addr", "listen address", "--workers", "(4)"} {
+			if !strings.Contains(usage, expected) {
+				t.Errorf("expected usage to contain %q, got:\n%s", expected, usage)
+			}
+		}
+		if strings.Contains(usage, "ADDR") {
+			t.Errorf("expected no environment variable with the cli value, got:\n%s", usage)
+		}
+	})
+
+	t.Run("usage writer", func(t *testing.T) {
+		var output bytes.Buffer
+
+		p := NewProcessor()
+		p.Output = &output
+		p.ReturnOnHelp = true
+		p.UsageWriter = func(w io.Writer, val string, fields []*Field) {
+			_, _ = io.WriteString(w, "custom "+val)
+		}
+
+		err := p.Process(context.Background(), &s)
+		if !errors.Is(err, ErrHelpRequested) {
+			t.Fatalf("expected ErrHelpRequested, got %v", err)
+		}
+
+		if output.String() != "custom cli" {
+			t.Errorf("unexpected usage: %q", output.String())
+		}
+	})
+}
+
+func TestUsageFormats(t *testing.T) {
+	var s struct {
+		Addr    string `key:"addr" description:"listen | address"`
+		Workers int    `key:"workers" default:"4" deprecated:"threads"`
+	}
+
+	fields := usageFields(t, &s)
+
+	t.Run("markdown", func(t *testing.T) {
+		var output bytes.Buffer
+		FprintUsageVal(&output, "markdown", fields)
+
+		expected := "| Key | Flag | Environment variable | Type | Default | Required | Description |\n" +
+			"| --- | --- | --- | --- | --- | --- | --- |\n" +
+			"| `addr` | `--addr` | `ADDR` | `string` |  | yes | listen \\| address |\n" +
+			"| `workers` | `--workers` | `WORKERS` | `int` | `4` | no | (deprecated: --threads, THREADS) |\n"
+		if output.String() != expected {
+			t.Errorf("unexpected markdown usage:\n%s", output.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var output bytes.Buffer
+		FprintUsageVal(&output, "json", fields)
+
+		var options []map[string]interface{}
+		err := json.Unmarshal(output.Bytes(), &options)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []map[string]interface{}{
+			{"key": "addr", "flag": "addr", "env": "ADDR", "description": "listen | address", "required": true, "type": "string"},
+			{"key": "workers", "flag": "workers", "env": "WORKERS", "default": "4", "required": false, "type": "int", "deprecated": []interface{}{"threads"}},
+		}
+		if !reflect.DeepEqual(options, expected) {
+			t.Errorf("unexpected json usage: %s", output.String())
+		}
+	})
+
+	t.Run("man", func(t *testing.T) {
+		var output bytes.Buffer
+		FprintUsageVal(&output, "man", fields)
+
+		usage := output.String()
+		for _, expected := range []string{
+			".SH OPTIONS\n",
+			".TP\n\\fB\\-\\-addr\\fR, \\fBADDR\\fR\nlisten | address\n.br\nType: string, required.\n",
+			".TP\n\\fB\\-\\-workers\\fR, \\fBWORKERS\\fR\n(deprecated: \\-\\-threads, THREADS)\n.br\nType: int, default: 4.\n",
+		} {
+			if !strings.Contains(usage, expected) {
+				t.Errorf("expected man usage to contain %q, got:\n%s", expected, usage)
+			}
+		}
+	})
+}
+
+func TestGroupedUsage(t *testing.T) {
+	var s struct {
+		Addr     string `key:"addr" description:"listen address"`
+		Database struct {
+			DSN     string `key:"dsn" description:"data source name"`
+			Pool    int    `key:"pool" default:"10"`
+			Replica string `key:"replica" required_if:"pool=1"`
+		} `key:"db" description:"Database connection"`
+		Cache *struct {
+			TTL int `key:"ttl"`
+		} `key:"cache" optional:"true" description:"Cache settings"`
+	}
+
+	var output bytes.Buffer
+	FprintUsageVal(&output, "", usageFields(t, &s))
+
+	usage := output.String()
+	expected := []string{"Required parameters:", "--addr", "Optional parameters:", "Cache settings:", "--cache.ttl", "Database connection:", "--db.dsn", "(required)", "--db.pool", "(10)", "--db.replica"}
+	var offset int
+	for _, e := range expected {
+		i := strings.Index(usage[offset:], e)
+		if i < 0 {
+			t.Fatalf("expected usage to contain %q after offset %d, got:\n%s", e, offset, usage)
+		}
+		offset += i + len(e)
+	}
+
+	for _, line := range strings.Split(usage, "\n") {
+		if (strings.Contains(line, "--cache.ttl") || strings.Contains(line, "--db.replica")) && strings.Contains(line, "(required)") {
+			t.Errorf("expected the optional and conditional keys not to be required, got %q", line)
+		}
+	}
+}
+
+func TestValuesUsage(t *testing.T) {
+	t.Setenv("ZCONFIG_TEST_ADDR", ":8080")
+	t.Setenv("ZCONFIG_TEST_PASSWORD", "hunter2")
+
+	var s struct {
+		Addr     string `key:"zconfig-test-addr"`
+		Password string `key:"zconfig-test-password" secret:"true"`
+		Workers  int    `key:"zconfig-test-workers" default:"4"`
+		Token    string `key:"zconfig-test-token"`
+	}
+
+	var output bytes.Buffer
+	FprintUsageVal(&output, "values", usageFields(t, &s))
+
+	lines := make(map[string]string)
+	for _, line := range strings.Split(output.String(), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) != 0 {
+			lines[parts[0]] = strings.Join(parts, " ")
+		}
+	}
+
+	for flag, expected := range map[string]string{
+		"--zconfig-test-addr":     "--zconfig-test-addr ZCONFIG_TEST_ADDR :8080 (env)",
+		"--zconfig-test-password": "--zconfig-test-password ZCONFIG_TEST_PASSWORD ****** (env)",
+		"--zconfig-test-workers":  "--zconfig-test-workers ZCONFIG_TEST_WORKERS 4 (default)",
+		"--zconfig-test-token":    "--zconfig-test-token ZCONFIG_TEST_TOKEN (missing)",
+	} {
+		if lines[flag] != expected {
+			t.Errorf("expected line %q, got %q", expected, lines[flag])
+		}
+	}
+
+	if strings.Contains(output.String(), "hunter2") {
+		t.Errorf("expected the secret to be masked, got:\n%s", output.String())
+	}
+}
+
+func TestProcessorValuesUsage(t *testing.T) {
+	oldArgs := Args
+	t.Cleanup(func() {
+		Args = oldArgs
+	})
+	Args = &ArgsProvider{Args: map[string]string{"help": "values"}}
+
+	var s struct {
+		Addr string `key:"addr"`
+	}
+
+	var repository Repository
+	repository.AddProviders(TestProvider{name: "test", values: map[string]string{"addr": ":8080"}})
+
+	for name, expected := range map[string]string{
+		"lookup":    "--addr ADDR :8080 (test)",
+		"no lookup": "--addr ADDR (unknown)",
+	} {
+		t.Run(name, func(t *testing.T) {
+			var output bytes.Buffer
+
+			p := NewProcessor(repository.Hook)
+			p.Output = &output
+			p.ReturnOnHelp = true
+			if name == "lookup" {
+				p.Lookup = repository.LookupField
+			}
+
+			err := p.Process(context.Background(), &s)
+			if !errors.Is(err, ErrHelpRequested) {
+				t.Fatalf("expected ErrHelpRequested, got %v", err)
+			}
+
+			if line := strings.Join(strings.Fields(output.String()), " "); !strings.Contains(line, expected) {
+				t.Errorf("expected usage to contain %q, got:\n%s", expected, output.String())
+			}
+		})
+	}
+}
+
+func TestManEscape(t *testing.T) {
+	for s, expected := range map[string]string{
+		"--addr":     "\\-\\-addr",
+		`C:\dir`:     `C:\edir`,
+		".hidden":    `\&.hidden`,
+		"two\nlines": "two lines",
+	} {
+		if escaped := manEscape(s); escaped != expected {
+			t.Errorf("manEscape(%q): expected %q, got %q", s, expected, escaped)
+		}
+	}
+}
+
+// usageFields returns the fields of s, as given to the usage functions.
+func usageFields(t *testing.T, s interface{}) []*Field {
+	t.Helper()
+
+	root, err := walk(reflect.ValueOf(s), reflect.StructField{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields, err := resolve(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mark(root, "")
+
+	return fields
+}
+
+func TestAliasesUsage(t *testing.T) {
+	for val, expected := range map[string]string{
+		"":    "(deprecated: --redis.address, REDIS_ADDRESS, --redis.host, REDIS_HOST)",
+		"cli": "(deprecated: --redis.address, --redis.host)",
+		"env": "(deprecated: REDIS_ADDRESS, REDIS_HOST)",
+	} {
+		usage := aliasesUsage(val, "deprecated", []string{"redis.address", "redis.host"})
+		if usage != expected {
+			t.Errorf("aliasesUsage(%q): expected %s, got %s", val, expected, usage)
+		}
+	}
+
+	if usage := aliasesUsage("", "alias", nil); usage != "" {
+		t.Errorf("expected an empty usage without aliases, got %s", usage)
+	}
+}
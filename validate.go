@@ -0,0 +1,40 @@
+package zconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Validatable is implemented by the structs checking their configuration as a
+// whole, e.g. a field that is required when another one is set.
+type Validatable interface {
+	Validate(context.Context) error
+}
+
+// Used for type comparison.
+var typeValidatable = reflect.TypeOf((*Validatable)(nil)).Elem()
+
+// Validate is a hook calling the Validate method of the fields implementing
+// Validatable. Executed after the hook of the repository and before
+// Initialize, it is called once all the fields are configured, and before any
+// of them is initialized. The optional fields left unset are not validated.
+func Validate(ctx context.Context, field *Field) error {
+	// Unset optional field, nothing to validate.
+	if field.isUnset() {
+		return nil
+	}
+
+	// Not validatable, nothing to do.
+	if !field.Value.Type().Implements(typeValidatable) {
+		return nil
+	}
+
+	// Validate the element itself via the interface.
+	err := field.Value.Interface().(Validatable).Validate(ctx)
+	if err != nil {
+		return fmt.Errorf("validating field: %w", err)
+	}
+
+	return nil
+}
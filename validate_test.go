@@ -0,0 +1,74 @@
+package zconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type validateTest struct {
+	TLS struct {
+		Enabled bool   `key:"enabled"`
+		Cert    string `key:"cert" default:""`
+	} `key:"tls"`
+
+	Validated   bool
+	Initialized bool
+}
+
+func (v *validateTest) Validate(ctx context.Context) error {
+	if v.Initialized {
+		return errors.New("validated after initialization")
+	}
+	v.Validated = true
+
+	if v.TLS.Enabled && v.TLS.Cert == "" {
+		return errors.New("tls.cert is required when tls.enabled is set")
+	}
+	return nil
+}
+
+func (v *validateTest) Init(ctx context.Context) error {
+	v.Initialized = true
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	for name, c := range map[string]struct {
+		values map[string]string
+		valid  bool
+	}{
+		"valid":   {map[string]string{"tls.enabled": "true", "tls.cert": "cert.pem"}, true},
+		"invalid": {map[string]string{"tls.enabled": "true"}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var repository Repository
+			repository.AddProviders(TestProvider{name: "test", values: c.values})
+			repository.AddParsers(ParseString)
+
+			s := new(validateTest)
+			err := NewProcessor(repository.Hook, Validate, Initialize).Process(context.Background(), s)
+
+			if !s.Validated {
+				t.Error("struct was not validated as expected")
+			}
+
+			if c.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !s.Initialized {
+					t.Error("struct was not initialized as expected")
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if s.Initialized {
+				t.Error("expected the invalid struct not to be initialized")
+			}
+		})
+	}
+}
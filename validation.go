@@ -0,0 +1,352 @@
+package zconfig
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const TagValidate = "validate"
+
+// A validationRule is one of the comma-separated rules of a `validate` tag,
+// e.g. `min=1`.
+type validationRule struct {
+	name string
+	arg  string
+}
+
+func (r validationRule) String() string {
+	if r.arg == "" {
+		return r.name
+	}
+	return r.name + "=" + r.arg
+}
+
+// validators checks a value against the argument of a rule. The returned
+// errors describe the rule, never the value, so secrets are not leaked.
+var validators = map[string]func(v reflect.Value, arg string) error{
+	"min":      validateMin,
+	"max":      validateMax,
+	"len":      validateLen,
+	"oneof":    validateOneOf,
+	"regex":    validateRegex,
+	"nonzero":  validateNonZero,
+	"url":      validateURL,
+	"hostport": validateHostPort,
+}
+
+// parseValidationRules splits a `validate` tag around its commas, a comma
+// being escaped by a backslash, e.g. `regex=^a{1\,3}$`.
+func parseValidationRules(tag string) (rules []validationRule, err error) {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			part.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(tag[i])
+		}
+	}
+	parts = append(parts, part.String())
+
+	for _, part := range parts {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+		if _, ok := validators[name]; !ok {
+			return nil, fmt.Errorf("unknown rule %s", name)
+		}
+		rules = append(rules, validationRule{name: name, arg: arg})
+	}
+
+	return rules, nil
+}
+
+// validateField checks the value of a configured field against the rules of
+// its `validate` tag.
+func validateField(f *Field, provider string) error {
+	tag, ok := f.Tags.Lookup(TagValidate)
+	if !ok {
+		return nil
+	}
+
+	rules, err := parseValidationRules(tag)
+	if err != nil {
+		return fmt.Errorf("invalid %s tag: %w", TagValidate, err)
+	}
+
+	v := validatedValue(f)
+	for _, rule := range rules {
+		err := validators[rule.name](v, rule.arg)
+		if err != nil {
+			return &ValidationError{
+				Path:     f.Path,
+				Key:      f.ConfigurationKey,
+				Provider: provider,
+				Rule:     rule.String(),
+				Err:      err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// validatedValue returns the value of a field checked by the rules: the value
+// pointed to for a pointer, and the value held by a Secret.
+func validatedValue(f *Field) reflect.Value {
+	v := f.Value
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.CanAddr() {
+		if secret, ok := v.Addr().Interface().(secretValue); ok {
+			v = reflect.ValueOf(secret.secretTarget()).Elem()
+		}
+	}
+	return v
+}
+
+// checkValidationTags makes sure that the `validate` tags of the configurable
+// fields only use known rules, with arguments applicable to the type of the
+// fields, so a typo is reported before any value is looked up, even for the
+// fields left unset.
+func checkValidationTags(fields []*Field) error {
+	for _, f := range fields {
+		tag, ok := f.Tags.Lookup(TagValidate)
+		if !ok || !f.Configurable {
+			continue
+		}
+
+		rules, err := parseValidationRules(tag)
+		if err == nil {
+			err = checkRuleArgs(validatedValue(f), rules)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s tag for field %s: %w", TagValidate, f.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// checkRuleArgs makes sure that the arguments of the rules can be used to
+// check the value, without checking the value itself.
+func checkRuleArgs(v reflect.Value, rules []validationRule) (err error) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "min", "max":
+			_, _, err = compareBound(v, rule.arg)
+		case "len":
+			switch v.Kind() {
+			case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+				_, err = compareLength(v, rule.arg)
+			default:
+				err = fmt.Errorf("not applicable to %s", v.Type())
+			}
+		case "regex":
+			_, err = regexp.Compile(rule.arg)
+			if err != nil {
+				err = fmt.Errorf("invalid regular expression: %w", err)
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", rule, err)
+		}
+	}
+
+	return nil
+}
+
+func validateMin(v reflect.Value, arg string) error {
+	cmp, length, err := compareBound(v, arg)
+	switch {
+	case err != nil:
+		return err
+	case cmp < 0 && length:
+		return fmt.Errorf("length must be at least %s", arg)
+	case cmp < 0:
+		return fmt.Errorf("must be at least %s", arg)
+	}
+	return nil
+}
+
+func validateMax(v reflect.Value, arg string) error {
+	cmp, length, err := compareBound(v, arg)
+	switch {
+	case err != nil:
+		return err
+	case cmp > 0 && length:
+		return fmt.Errorf("length must be at most %s", arg)
+	case cmp > 0:
+		return fmt.Errorf("must be at most %s", arg)
+	}
+	return nil
+}
+
+// compareBound compares a number to a bound parsed into the same type (e.g.
+// `1s` for a time.Duration), or the length of a string, slice or map to an
+// integer bound, which is reported by length.
+func compareBound(v reflect.Value, arg string) (cmp int, length bool, err error) {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		cmp, err = compareLength(v, arg)
+		return cmp, true, err
+	}
+
+	bound := reflect.New(v.Type())
+	err = ParseString(arg, bound.Interface())
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid bound %s: %w", arg, err)
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compare(v.Int(), bound.Elem().Int()), false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compare(v.Uint(), bound.Elem().Uint()), false, nil
+	case reflect.Float32, reflect.Float64:
+		return compare(v.Float(), bound.Elem().Float()), false, nil
+	}
+
+	return 0, false, fmt.Errorf("not applicable to %s", v.Type())
+}
+
+func compare[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareLength compares the length of a string (in runes), slice or map to
+// an integer bound.
+func compareLength(v reflect.Value, arg string) (int, error) {
+	bound, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid length %s: %w", arg, err)
+	}
+
+	length := v.Len()
+	if v.Kind() == reflect.String {
+		length = utf8.RuneCountInString(v.String())
+	}
+
+	return compare(int64(length), int64(bound)), nil
+}
+
+func validateLen(v reflect.Value, arg string) error {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+	default:
+		return fmt.Errorf("not applicable to %s", v.Type())
+	}
+
+	cmp, err := compareLength(v, arg)
+	if err != nil {
+		return err
+	}
+	if cmp != 0 {
+		return fmt.Errorf("length must be %s", arg)
+	}
+	return nil
+}
+
+func validateOneOf(v reflect.Value, arg string) error {
+	values := strings.Split(arg, "|")
+	return validateElements(v, func(s string) error {
+		for _, value := range values {
+			if s == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+	})
+}
+
+func validateRegex(v reflect.Value, arg string) error {
+	re, err := regexp.Compile(arg)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	return validateElements(v, func(s string) error {
+		if !re.MatchString(s) {
+			return fmt.Errorf("must match %s", arg)
+		}
+		return nil
+	})
+}
+
+func validateNonZero(v reflect.Value, _ string) error {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return errors.New("must not be empty")
+		}
+	default:
+		if v.IsZero() {
+			return errors.New("must not be zero")
+		}
+	}
+	return nil
+}
+
+func validateURL(v reflect.Value, _ string) error {
+	return validateElements(v, func(s string) error {
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("must be an absolute URL")
+		}
+		return nil
+	})
+}
+
+func validateHostPort(v reflect.Value, _ string) error {
+	return validateElements(v, func(s string) error {
+		_, port, err := net.SplitHostPort(s)
+		if err != nil {
+			return errors.New("must be a host:port address")
+		}
+		_, err = strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return errors.New("must be a host:port address with a numeric port")
+		}
+		return nil
+	})
+}
+
+// validateElements checks the elements of a slice or an array, or else the
+// value itself, formatted as strings.
+func validateElements(v reflect.Value, check func(s string) error) error {
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return check(string(v.Bytes()))
+	case v.Kind() != reflect.Slice && v.Kind() != reflect.Array:
+		return check(fmt.Sprint(v.Interface()))
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		err := check(fmt.Sprint(v.Index(i).Interface()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
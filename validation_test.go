@@ -0,0 +1,202 @@
+package zconfig
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidation(t *testing.T) {
+	for name, c := range map[string]struct {
+		s     interface{}
+		raw   string
+		valid bool
+	}{
+		"min int": {new(struct {
+			V int `key:"v" validate:"min=1,max=65535"`
+		}), "-1", false},
+		"max int": {new(struct {
+			V int `key:"v" validate:"min=1,max=65535"`
+		}), "65536", false},
+		"int in range": {new(struct {
+			V int `key:"v" validate:"min=1,max=65535"`
+		}), "8080", true},
+		"min uint": {new(struct {
+			V uint `key:"v" validate:"min=2"`
+		}), "1", false},
+		"max float": {new(struct {
+			V float64 `key:"v" validate:"max=0.5"`
+		}), "0.75", false},
+		"min duration": {new(struct {
+			V time.Duration `key:"v" validate:"min=1s"`
+		}), "500ms", false},
+		"duration in range": {new(struct {
+			V time.Duration `key:"v" validate:"min=1s,max=1m"`
+		}), "30s", true},
+		"min string length": {new(struct {
+			V string `key:"v" validate:"min=3"`
+		}), "ab", false},
+		"max slice length": {new(struct {
+			V []string `key:"v" validate:"max=2"`
+		}), "a,b,c", false},
+		"len": {new(struct {
+			V string `key:"v" validate:"len=3"`
+		}), "été", true},
+		"wrong len": {new(struct {
+			V []int `key:"v" validate:"len=3"`
+		}), "1,2", false},
+		"oneof": {new(struct {
+			V string `key:"v" validate:"oneof=debug|info|warn"`
+		}), "info", true},
+		"not oneof": {new(struct {
+			V string `key:"v" validate:"oneof=debug|info|warn"`
+		}), "trace", false},
+		"oneof elements": {new(struct {
+			V []string `key:"v" validate:"oneof=a|b"`
+		}), "a,c", false},
+		"regex": {new(struct {
+			V string `key:"v" validate:"regex=^[a-z]+$"`
+		}), "foo", true},
+		"no regex match": {new(struct {
+			V string `key:"v" validate:"regex=^[a-z]+$"`
+		}), "Foo", false},
+		"escaped comma": {new(struct {
+			V string `key:"v" validate:"regex=^a{1\\,3}$,nonzero"`
+		}), "aaa", true},
+		"nonzero": {new(struct {
+			V int `key:"v" validate:"nonzero"`
+		}), "0", false},
+		"nonzero string": {new(struct {
+			V string `key:"v" validate:"nonzero"`
+		}), "", false},
+		"url": {new(struct {
+			V string `key:"v" validate:"url"`
+		}), "https://example.com/path", true},
+		"relative url": {new(struct {
+			V string `key:"v" validate:"url"`
+		}), "/path", false},
+		"hostport": {new(struct {
+			V string `key:"v" validate:"hostport"`
+		}), ":8080", true},
+		"missing port": {new(struct {
+			V string `key:"v" validate:"hostport"`
+		}), "localhost", false},
+		"named port": {new(struct {
+			V string `key:"v" validate:"hostport"`
+		}), "localhost:http", false},
+		"pointer": {new(struct {
+			V *int `key:"v" validate:"min=1"`
+		}), "0", false},
+		"secret": {new(struct {
+			V Secret[string] `key:"v" validate:"len=4"`
+		}), "1234", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var repository Repository
+			repository.AddProviders(TestProvider{name: "test", values: map[string]string{"v": c.raw}})
+			repository.AddParsers(ParseString)
+
+			err := NewProcessor(repository.Hook).Process(context.Background(), c.s)
+			if c.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			if validationErr.Key != "v" || validationErr.Provider != "test" || validationErr.Rule == "" {
+				t.Errorf("unexpected error: %#v", validationErr)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	var repository Repository
+	repository.AddProviders(TestProvider{name: "test", values: map[string]string{"port": "-1", "token": "hunter2"}})
+	repository.AddParsers(ParseString)
+
+	var s struct {
+		Port  int    `key:"port" validate:"min=1,max=65535"`
+		Token string `key:"token" secret:"true" validate:"min=8"`
+	}
+
+	processor := NewProcessor(repository.Hook)
+	processor.CollectErrors = true
+	err := processor.Process(context.Background(), &s)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	for _, expected := range []string{
+		"validating value for key port from test: rule min=1: must be at least 1",
+		"validating value for key token from test: rule min=8: length must be at least 8",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("expected the secret not to be part of the error, got %v", err)
+	}
+}
+
+func TestValidationTags(t *testing.T) {
+	for name, c := range map[string]struct {
+		s        interface{}
+		expected string
+	}{
+		"unknown rule": {new(struct {
+			V *int `key:"v" optional:"true" validate:"mn=1"`
+		}), "invalid validate tag for field $.V: unknown rule mn"},
+		"invalid bound": {new(struct {
+			V time.Duration `key:"v" required_if:"w" validate:"min=1x"`
+			W bool          `key:"w" default:"false"`
+		}), "invalid validate tag for field $.V: rule min=1x: invalid bound 1x"},
+		"invalid length": {new(struct {
+			V []string `key:"v" validate:"max=two"`
+		}), "invalid validate tag for field $.V: rule max=two: invalid length two"},
+		"not applicable": {new(struct {
+			V bool `key:"v" validate:"len=1"`
+		}), "invalid validate tag for field $.V: rule len=1: not applicable to bool"},
+		"invalid regex": {new(struct {
+			V Secret[string] `key:"v" validate:"regex=^(a"`
+		}), "invalid validate tag for field $.V: rule regex=^(a: invalid regular expression"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			hook := func(ctx context.Context, field *Field) error {
+				t.Fatalf("unexpected hook execution on field %s", field.Path)
+				return nil
+			}
+
+			err := NewProcessor(hook).Process(context.Background(), c.s)
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("expected an error containing %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseValidationRules(t *testing.T) {
+	rules, err := parseValidationRules(`min=1, regex=^a\,b$,nonzero`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []validationRule{{"min", "1"}, {"regex", "^a,b$"}, {"nonzero", ""}}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("unexpected rules: %v", rules)
+	}
+
+	_, err = parseValidationRules("positive")
+	if err == nil {
+		t.Error("expected an error for an unknown rule, got nil")
+	}
+}
@@ -2,6 +2,8 @@ package zconfig
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 )
 
 var (
@@ -10,6 +12,12 @@ var (
 	Args              = NewArgsProvider()
 	Env               = NewEnvProvider()
 	Dotenv            = NewDotenvProvider()
+
+	// Name of the application the configuration file is looked up for, and
+	// whether the configuration files were loaded into the default
+	// repository.
+	appName           = filepath.Base(os.Args[0])
+	configFilesLoaded bool
 )
 
 func init() {
@@ -21,8 +29,31 @@ func init() {
 	}
 
 	DefaultRepository.AddProviders(Args, Env, Dotenv)
-	DefaultRepository.AddParsers(ParseString)
-	DefaultProcessor.AddHooks(DefaultRepository.Hook, Initialize)
+	DefaultRepository.AddParsers(ParseString, ParseNative)
+	DefaultProcessor.AddHooks(DefaultRepository.Hook, CheckRequirements, Validate, Initialize)
+	DefaultProcessor.Completion = true
+	DefaultProcessor.Exists = DefaultRepository.Exists
+	DefaultProcessor.Setup = loadConfigFiles
+	DefaultProcessor.Lookup = DefaultRepository.LookupField
+}
+
+// SetEnvPrefix sets the prefix of the environment variables looked up by the
+// default repository and displayed by the default usage message, e.g. `MYAPP`
+// to lookup the `server.addr` key in the `MYAPP_SERVER_ADDR` variable. It
+// should be called before Configure.
+func SetEnvPrefix(prefix string) {
+	old := Env
+	Env = NewEnvProviderWithPrefix(prefix)
+	DefaultRepository.replaceProvider(old, Env)
+}
+
+// SetAppName sets the name of the application the default processor looks up
+// a configuration file for when none is given by the `--config` flag or the
+// `CONFIG_FILE` environment variable, e.g. `myapp` for `/etc/myapp/config.yaml`.
+// It defaults to the name of the running binary, and an empty name disables
+// the lookup. It should be called before Configure.
+func SetAppName(name string) {
+	appName = name
 }
 
 // Configure a service using the default processor.
@@ -47,11 +78,35 @@ type Provider interface {
 	Priority() int
 }
 
+// SourceProvider is the interface implemented by providers able to tell more
+// precisely than their name where a value comes from, e.g. a file referenced by
+// an environment variable. When the returned source is not empty, it is used
+// instead of the provider's name as the provider of the field.
+type SourceProvider interface {
+	Provider
+	RetrieveSource(key string) (value interface{}, source string, found bool, err error)
+}
+
+// FieldProvider is the interface implemented by providers that take the
+// definition of the field into account when retrieving its value, e.g. to
+// honor tags overriding the names derived from the configuration key. The
+// returned source follows the same rules as for a SourceProvider.
+type FieldProvider interface {
+	Provider
+	RetrieveField(f *Field) (value interface{}, source string, found bool, err error)
+}
+
 // Add a provider to the default repository.
 func AddProviders(providers ...Provider) {
 	DefaultRepository.AddProviders(providers...)
 }
 
+// Logger is the interface used to report non-fatal events, such as the use
+// of deprecated keys. It is implemented by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
 // Parser is the type of function that can convert a raw representation to a
 // given type.
 type Parser func(interface{}, interface{}) error